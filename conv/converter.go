@@ -3,12 +3,16 @@ package conv
 import (
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unsafe"
+
+	"github.com/viant/tagly/format/text"
 )
 
 // DefaultDateLayout is the default layout used for time parsing when no layout is specified
@@ -28,6 +32,22 @@ type Options struct {
 	ClonePointerData bool
 	// AccessUnexported if true, allows accessing unexported fields
 	AccessUnexported bool
+	// LossPolicy controls whether overflow, truncation, NaN and out-of-range timestamps are rejected
+	LossPolicy LossPolicy
+	// CollectErrors if true, records every failing field, element or entry as a ConversionError and
+	// carries on; Convert then returns ConversionErrors. Otherwise unconvertible struct fields are skipped.
+	CollectErrors bool
+	// CaseFormat formats map keys of fields without a TagName tag, e.g. text.CaseFormatLowerCamel
+	CaseFormat text.CaseFormat
+	// DateLayouts lists layouts tried after DateLayout when parsing time strings; defaults to RFC3339 and ISO date forms
+	DateLayouts []string
+	// Location is used for time strings without a zone and for unix timestamps; nil parses in UTC
+	// and keeps timestamps in the local zone
+	Location *time.Location
+	// EpochUnit sets the unit of numeric unix timestamps, in both directions
+	EpochUnit EpochUnit
+	// OnlySetFields if true, StructToMap leaves out fields not flagged by a setMarker holder
+	OnlySetFields bool
 }
 
 // DefaultOptions returns default conversion options
@@ -43,8 +63,15 @@ func DefaultOptions() Options {
 type Converter struct {
 	options       Options
 	structCache   sync.Map // map[reflect.Type]*structInfo
+	planCache     sync.Map // map[typeKey]*structPlan
 	customConvMap sync.Map // map[typeKey]ConversionFunc
-	structTypeMap sync.Map // map[string]bool
+	mappings      sync.Map // map[typeKey][]FieldMapping
+	enums         sync.Map // map[reflect.Type]*enum
+	// parent supplies conversions and mappings not registered on this converter
+	parent *Converter
+	// generation counts registrations; planGeneration is the lineage generation the plan cache was built for
+	generation     atomic.Uint64
+	planGeneration atomic.Uint64
 }
 
 // ConversionFunc defines a custom conversion function
@@ -62,9 +89,45 @@ func NewConverter(options Options) *Converter {
 	}
 }
 
+// NewChild creates a converter with its own options that inherits conversions and mappings registered
+// on c, including later ones; registrations on the child override the parent's and do not affect it.
+func (c *Converter) NewChild(options Options) *Converter {
+	ret := NewConverter(options)
+	ret.parent = c
+	ret.planGeneration.Store(ret.lineageGeneration())
+	return ret
+}
+
+// Options returns the converter options
+func (c *Converter) Options() Options {
+	return c.options
+}
+
 // RegisterConversion registers a custom conversion function between source and destination types
 func (c *Converter) RegisterConversion(srcType, destType reflect.Type, fn ConversionFunc) {
 	c.customConvMap.Store(typeKey{srcType, destType}, fn)
+	// compiled plans copy identical field types directly, so they are rebuilt against the new registry
+	c.planCache.Clear()
+	c.generation.Add(1)
+}
+
+// conversion returns the custom conversion for key registered on c or its ancestors
+func (c *Converter) conversion(key typeKey) (ConversionFunc, bool) {
+	for ; c != nil; c = c.parent {
+		if v, ok := c.customConvMap.Load(key); ok {
+			return v.(ConversionFunc), true
+		}
+	}
+	return nil, false
+}
+
+// lineageGeneration sums registration generations of c and its ancestors
+func (c *Converter) lineageGeneration() uint64 {
+	var ret uint64
+	for ; c != nil; c = c.parent {
+		ret += c.generation.Load()
+	}
+	return ret
 }
 
 // Convert converts the source value to the destination value
@@ -95,8 +158,8 @@ func (c *Converter) Convert(src interface{}, dest interface{}) error {
 	destElemType := destValue.Elem().Type()
 
 	// Try custom conversion first
-	if v, ok := c.customConvMap.Load(typeKey{srcType, destElemType}); ok {
-		return v.(ConversionFunc)(src, dest, c.options)
+	if fn, ok := c.conversion(typeKey{srcType, destElemType}); ok {
+		return fn(src, dest, c.options)
 	}
 
 	// Handle direct assignability
@@ -108,6 +171,12 @@ func (c *Converter) Convert(src interface{}, dest interface{}) error {
 		return nil
 	}
 
+	if srcType.PkgPath() != "" || destElemType.PkgPath() != "" {
+		if handled, err := c.convertNamed(destValue, srcValue, src); handled {
+			return err
+		}
+	}
+
 	// Check for primitive type conversions first, before using general ConvertibleTo
 	destKind := destElemType.Kind()
 
@@ -118,6 +187,9 @@ func (c *Converter) Convert(src interface{}, dest interface{}) error {
 	case reflect.Bool:
 		return c.convertToBool(destValue, srcValue)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if destElemType == durationType {
+			return c.convertToDuration(destValue, srcValue)
+		}
 		return c.convertToInt(destValue, srcValue)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		return c.convertToUint(destValue, srcValue)
@@ -163,79 +235,6 @@ func (c *Converter) clonePointerValue(destValue, srcValue reflect.Value) error {
 	return nil
 }
 
-// generateStructSignature generates a signature for a struct type for quick comparison
-func (c *Converter) generateStructSignature(rType reflect.Type) string {
-	if rType.Kind() != reflect.Struct {
-		return ""
-	}
-
-	var sb strings.Builder
-	sb.WriteString(rType.PkgPath())
-	sb.WriteRune(':')
-
-	for i := 0; i < rType.NumField(); i++ {
-		field := rType.Field(i)
-		sb.WriteString(field.Name)
-		sb.WriteRune(':')
-		sb.WriteString(field.Type.String())
-		sb.WriteRune(';')
-	}
-
-	return sb.String()
-}
-
-// areStructTypesCompatible checks if two struct types have compatible memory layouts
-func (c *Converter) areStructTypesCompatible(srcType, destType reflect.Type) bool {
-	if srcType.Kind() != reflect.Struct || destType.Kind() != reflect.Struct {
-		return false
-	}
-
-	if srcType.NumField() != destType.NumField() {
-		return false
-	}
-
-	// Use xreflect to check if types are the same
-	srcSig := c.generateStructSignature(srcType)
-	destSig := c.generateStructSignature(destType)
-
-	// If we've already determined these are compatible, return quickly
-	key := srcSig + "->" + destSig
-	if v, ok := c.structTypeMap.Load(key); ok {
-		return v.(bool)
-	}
-
-	// First time checking these types
-	// We consider them compatible if fields match and types are the same or convertible
-	compatible := true
-
-	for i := 0; i < srcType.NumField(); i++ {
-		srcField := srcType.Field(i)
-		destField := destType.Field(i)
-
-		if srcField.Name != destField.Name {
-			compatible = false
-			break
-		}
-
-		if srcField.Type != destField.Type && !srcField.Type.ConvertibleTo(destField.Type) {
-			// If these are structs themselves, recursively check
-			if srcField.Type.Kind() == reflect.Struct && destField.Type.Kind() == reflect.Struct {
-				if !c.areStructTypesCompatible(srcField.Type, destField.Type) {
-					compatible = false
-					break
-				}
-			} else {
-				compatible = false
-				break
-			}
-		}
-	}
-
-	// Cache the result
-	c.structTypeMap.Store(key, compatible)
-	return compatible
-}
-
 func (c *Converter) convertComplex(destValue, srcValue reflect.Value) error {
 	// Ensure we're working with the right value
 	srcValue = indirect(srcValue)
@@ -247,10 +246,10 @@ func (c *Converter) convertComplex(destValue, srcValue reflect.Value) error {
 	destKind := destType.Kind()
 	srcKind := srcValue.Kind()
 
-	// Special optimization for struct to struct conversion when types have the same signature
+	// Special optimization for struct to struct conversion when types share a layout
 	if srcKind == reflect.Struct && destKind == reflect.Struct {
 		srcType := srcValue.Type()
-		if c.areStructTypesCompatible(srcType, destType) {
+		if c.structPlanFor(srcType, destType).convertible {
 			// Direct memory copy for same layout structs
 			destValue.Elem().Set(srcValue.Convert(destType))
 			return nil
@@ -282,6 +281,10 @@ func (c *Converter) convertToString(destValue, srcValue reflect.Value) error {
 	case reflect.Bool:
 		result = strconv.FormatBool(srcValue.Bool())
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if srcValue.Type() == durationType {
+			result = srcValue.Interface().(time.Duration).String()
+			break
+		}
 		result = strconv.FormatInt(srcValue.Int(), 10)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		result = strconv.FormatUint(srcValue.Uint(), 10)
@@ -295,6 +298,11 @@ func (c *Converter) convertToString(destValue, srcValue reflect.Value) error {
 		} else {
 			return fmt.Errorf("cannot convert %v to string", srcValue.Type())
 		}
+	case reflect.Struct:
+		if srcValue.Type() != timeType {
+			return fmt.Errorf("cannot convert %v to string", srcValue.Type())
+		}
+		result = c.formatTime(srcValue.Interface().(time.Time))
 	default:
 		return fmt.Errorf("cannot convert %v to string", srcValue.Type())
 	}
@@ -342,8 +350,16 @@ func (c *Converter) convertToInt(destValue, srcValue reflect.Value) error {
 		result = srcValue.Int()
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		v := srcValue.Uint()
+		if v > math.MaxInt64 && c.strict() {
+			return lossError("%d overflows int64", v)
+		}
 		result = int64(v)
 	case reflect.Float32, reflect.Float64:
+		if c.strict() {
+			if err := floatToIntLoss(srcValue.Float()); err != nil {
+				return err
+			}
+		}
 		result = int64(srcValue.Float())
 	case reflect.Bool:
 		if srcValue.Bool() {
@@ -354,6 +370,9 @@ func (c *Converter) convertToInt(destValue, srcValue reflect.Value) error {
 		if strings.Contains(srcValue.String(), ".") {
 			var f float64
 			f, err = strconv.ParseFloat(srcValue.String(), 64)
+			if err == nil && c.strict() {
+				err = floatToIntLoss(f)
+			}
 			result = int64(f)
 		} else {
 			result, err = strconv.ParseInt(srcValue.String(), 0, 64)
@@ -361,14 +380,34 @@ func (c *Converter) convertToInt(destValue, srcValue reflect.Value) error {
 		if err != nil {
 			return err
 		}
+	case reflect.Struct:
+		if srcValue.Type() != timeType {
+			return fmt.Errorf("cannot convert %v to int", srcValue.Type())
+		}
+		result = c.timeEpoch(srcValue.Interface().(time.Time))
 	default:
 		return fmt.Errorf("cannot convert %v to int", srcValue.Type())
 	}
 
+	if c.strict() && destValue.Elem().OverflowInt(result) {
+		return lossError("%d overflows %v", result, destValue.Elem().Type())
+	}
 	destValue.Elem().SetInt(result)
 	return nil
 }
 
+func (c *Converter) convertToDuration(destValue, srcValue reflect.Value) error {
+	if srcValue.Kind() != reflect.String {
+		return c.convertToInt(destValue, srcValue)
+	}
+	d, err := parseDuration(srcValue.String())
+	if err != nil {
+		return err
+	}
+	destValue.Elem().SetInt(int64(d))
+	return nil
+}
+
 func (c *Converter) convertToUint(destValue, srcValue reflect.Value) error {
 	var result uint64
 
@@ -386,6 +425,11 @@ func (c *Converter) convertToUint(destValue, srcValue reflect.Value) error {
 		if v < 0 {
 			return fmt.Errorf("cannot convert negative value %f to unsigned int", v)
 		}
+		if c.strict() {
+			if err := floatToUintLoss(v); err != nil {
+				return err
+			}
+		}
 		result = uint64(v)
 	case reflect.Bool:
 		if srcValue.Bool() {
@@ -399,6 +443,9 @@ func (c *Converter) convertToUint(destValue, srcValue reflect.Value) error {
 			if f < 0 {
 				return fmt.Errorf("cannot convert negative value %f to unsigned int", f)
 			}
+			if err == nil && c.strict() {
+				err = floatToUintLoss(f)
+			}
 			result = uint64(f)
 		} else {
 			result, err = strconv.ParseUint(srcValue.String(), 0, 64)
@@ -410,6 +457,9 @@ func (c *Converter) convertToUint(destValue, srcValue reflect.Value) error {
 		return fmt.Errorf("cannot convert %v to uint", srcValue.Type())
 	}
 
+	if c.strict() && destValue.Elem().OverflowUint(result) {
+		return lossError("%d overflows %v", result, destValue.Elem().Type())
+	}
 	destValue.Elem().SetUint(result)
 	return nil
 }
@@ -419,9 +469,21 @@ func (c *Converter) convertToFloat(destValue, srcValue reflect.Value) error {
 
 	switch srcValue.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		result = float64(srcValue.Int())
+		v := srcValue.Int()
+		magnitude := uint64(v)
+		if v < 0 {
+			magnitude = -magnitude
+		}
+		if c.strict() && !exactInFloat(magnitude, floatMantissa(destValue.Elem().Type().Bits())) {
+			return lossError("%d cannot be represented exactly as %v", v, destValue.Elem().Type())
+		}
+		result = float64(v)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		result = float64(srcValue.Uint())
+		v := srcValue.Uint()
+		if c.strict() && !exactInFloat(v, floatMantissa(destValue.Elem().Type().Bits())) {
+			return lossError("%d cannot be represented exactly as %v", v, destValue.Elem().Type())
+		}
+		result = float64(v)
 	case reflect.Float32, reflect.Float64:
 		result = srcValue.Float()
 	case reflect.Bool:
@@ -438,6 +500,9 @@ func (c *Converter) convertToFloat(destValue, srcValue reflect.Value) error {
 		return fmt.Errorf("cannot convert %v to float", srcValue.Type())
 	}
 
+	if c.strict() && !math.IsInf(result, 0) && destValue.Elem().OverflowFloat(result) {
+		return lossError("%v overflows %v", result, destValue.Elem().Type())
+	}
 	destValue.Elem().SetFloat(result)
 	return nil
 }
@@ -448,59 +513,23 @@ func (c *Converter) convertToTime(destValue, srcValue reflect.Value) error {
 
 	switch srcValue.Kind() {
 	case reflect.String:
-		layout := c.options.DateLayout
-		if layout == "" {
-			layout = DefaultDateLayout
-		}
-
-		// Try to parse with the specified layout
-		t, err = time.Parse(layout, srcValue.String())
-		if err != nil {
-			// Try RFC3339 as a fallback
-			t, err = time.Parse(time.RFC3339, srcValue.String())
-			if err != nil {
-				// Try other common formats
-				formats := []string{
-					time.RFC3339Nano,
-					time.RFC3339,
-					"2006-01-02T15:04:05",
-					"2006-01-02 15:04:05",
-					"2006-01-02",
-				}
-
-				for _, format := range formats {
-					t, err = time.Parse(format, srcValue.String())
-					if err == nil {
-						break
-					}
-				}
-
-				if err != nil {
-					return fmt.Errorf("cannot parse time string '%s': %w", srcValue.String(), err)
-				}
-			}
+		if t, err = c.parseTime(srcValue.String()); err != nil {
+			return err
 		}
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		unixTime := srcValue.Int()
-		if unixTime > 1e10 { // Assuming nanoseconds if value is very large
-			t = time.Unix(0, unixTime)
-		} else {
-			t = time.Unix(unixTime, 0)
-		}
+		t = c.epochTime(srcValue.Int())
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		unixTime := int64(srcValue.Uint())
-		if unixTime > 1e10 { // Assuming nanoseconds if value is very large
-			t = time.Unix(0, unixTime)
-		} else {
-			t = time.Unix(unixTime, 0)
+		if c.strict() && srcValue.Uint() > math.MaxInt64 {
+			return lossError("unix timestamp %d is out of range", srcValue.Uint())
 		}
+		t = c.epochTime(int64(srcValue.Uint()))
 	case reflect.Float32, reflect.Float64:
-		unixTime := int64(srcValue.Float())
-		fractional := srcValue.Float() - float64(unixTime)
-		nanos := int64(fractional * 1e9)
-		t = time.Unix(unixTime, nanos)
+		if f := srcValue.Float(); c.strict() && (math.IsNaN(f) || math.IsInf(f, 0) || math.Abs(f) >= math.MaxInt64) {
+			return lossError("unix timestamp %v is out of range", f)
+		}
+		t = c.floatEpochTime(srcValue.Float())
 	case reflect.Struct:
-		if srcValue.Type() == reflect.TypeOf(time.Time{}) {
+		if srcValue.Type() == timeType {
 			t = srcValue.Interface().(time.Time)
 		} else {
 			return fmt.Errorf("cannot convert struct %v to time.Time", srcValue.Type())
@@ -509,6 +538,11 @@ func (c *Converter) convertToTime(destValue, srcValue reflect.Value) error {
 		return fmt.Errorf("cannot convert %v to time.Time", srcValue.Type())
 	}
 
+	if c.strict() && srcValue.Kind() != reflect.String && srcValue.Kind() != reflect.Struct {
+		if err := timeRangeLoss(t, srcValue.Interface()); err != nil {
+			return err
+		}
+	}
 	destValue.Elem().Set(reflect.ValueOf(t))
 	return nil
 }
@@ -516,6 +550,7 @@ func (c *Converter) convertToTime(destValue, srcValue reflect.Value) error {
 func (c *Converter) convertToSlice(destValue, srcValue reflect.Value) error {
 	destType := destValue.Type().Elem()
 	destElemType := destType.Elem()
+	var errs ConversionErrors
 
 	// Special case: []byte to string conversion
 	if destElemType.Kind() == reflect.Uint8 && srcValue.Kind() == reflect.String {
@@ -536,14 +571,16 @@ func (c *Converter) convertToSlice(destValue, srcValue reflect.Value) error {
 
 			// Convert map[string]interface{} to struct
 			if err := c.Convert(elemValue.Interface(), elemPtr.Interface()); err != nil {
-				return fmt.Errorf("error converting slice element %d: %w", i, err)
+				if err = c.failure(&errs, err, fmt.Sprintf("[%d]", i), false); err != nil {
+					return err
+				}
 			}
 
 			sliceValue.Index(i).Set(elemPtr.Elem())
 		}
 
 		destValue.Elem().Set(sliceValue)
-		return nil
+		return errs.err()
 	}
 
 	// Special case handling for []interface{} to []string conversion
@@ -558,14 +595,16 @@ func (c *Converter) convertToSlice(destValue, srcValue reflect.Value) error {
 			elemPtr := reflect.New(destElemType)
 
 			if err := c.Convert(elemValue.Interface(), elemPtr.Interface()); err != nil {
-				return fmt.Errorf("error converting slice element %d: %w", i, err)
+				if err = c.failure(&errs, err, fmt.Sprintf("[%d]", i), false); err != nil {
+					return err
+				}
 			}
 
 			sliceValue.Index(i).Set(elemPtr.Elem())
 		}
 
 		destValue.Elem().Set(sliceValue)
-		return nil
+		return errs.err()
 	}
 
 	if srcValue.Kind() != reflect.Slice && srcValue.Kind() != reflect.Array {
@@ -579,7 +618,7 @@ func (c *Converter) convertToSlice(destValue, srcValue reflect.Value) error {
 
 		sliceValue.Index(0).Set(elemPtr.Elem())
 		destValue.Elem().Set(sliceValue)
-		return nil
+		return errs.err()
 	}
 
 	length := srcValue.Len()
@@ -592,7 +631,9 @@ func (c *Converter) convertToSlice(destValue, srcValue reflect.Value) error {
 			elemValue := reflect.New(destElemType.Elem())
 			// Convert source value to the pointed-to type
 			if err := c.Convert(srcValue.Index(i).Interface(), elemValue.Interface()); err != nil {
-				return fmt.Errorf("error converting slice element %d: %w", i, err)
+				if err = c.failure(&errs, err, fmt.Sprintf("[%d]", i), false); err != nil {
+					return err
+				}
 			}
 			// Set the pointer value in the slice
 			sliceValue.Index(i).Set(elemValue)
@@ -600,14 +641,16 @@ func (c *Converter) convertToSlice(destValue, srcValue reflect.Value) error {
 			// Original code for non-pointer element types
 			elemPtr := reflect.New(destElemType)
 			if err := c.Convert(srcValue.Index(i).Interface(), elemPtr.Interface()); err != nil {
-				return fmt.Errorf("error converting slice element %d: %w", i, err)
+				if err = c.failure(&errs, err, fmt.Sprintf("[%d]", i), false); err != nil {
+					return err
+				}
 			}
 			sliceValue.Index(i).Set(elemPtr.Elem())
 		}
 	}
 
 	destValue.Elem().Set(sliceValue)
-	return nil
+	return errs.err()
 }
 
 func (c *Converter) convertToMap(destValue, srcValue reflect.Value) error {
@@ -616,6 +659,7 @@ func (c *Converter) convertToMap(destValue, srcValue reflect.Value) error {
 	destValType := destType.Elem()
 
 	mapValue := reflect.MakeMap(destType)
+	var errs ConversionErrors
 
 	// Handle struct to map conversion
 	if srcValue.Kind() == reflect.Struct {
@@ -640,13 +684,19 @@ func (c *Converter) convertToMap(destValue, srcValue reflect.Value) error {
 			// Convert the field value to map value type
 			valPtr := reflect.New(destValType)
 			if err := c.Convert(fieldValue.Interface(), valPtr.Interface()); err != nil {
-				return fmt.Errorf("error converting field %s: %w", field.Name, err)
+				partial := isPartial(err)
+				if err = c.failure(&errs, err, field.Name, false); err != nil {
+					return err
+				}
+				if !partial {
+					continue
+				}
 			}
 
 			// Convert field name to map key type
 			keyPtr := reflect.New(destKeyType)
 			if err := c.Convert(keyName, keyPtr.Interface()); err != nil {
-				return fmt.Errorf("error converting field name %s to key type: %w", field.Name, err)
+				return withPath(fmt.Errorf("cannot use field name as %v key: %w", destKeyType, err), field.Name)
 			}
 
 			mapValue.SetMapIndex(keyPtr.Elem(), valPtr.Elem())
@@ -655,13 +705,23 @@ func (c *Converter) convertToMap(destValue, srcValue reflect.Value) error {
 		iter := srcValue.MapRange()
 		for iter.Next() {
 			keyPtr := reflect.New(destKeyType)
+			segment := fmt.Sprintf("[%v]", iter.Key().Interface())
 			if err := c.Convert(iter.Key().Interface(), keyPtr.Interface()); err != nil {
-				return fmt.Errorf("error converting map key: %w", err)
+				if err = c.failure(&errs, fmt.Errorf("invalid key: %w", err), segment, false); err != nil {
+					return err
+				}
+				continue
 			}
 
 			valPtr := reflect.New(destValType)
 			if err := c.Convert(iter.Value().Interface(), valPtr.Interface()); err != nil {
-				return fmt.Errorf("error converting map value: %w", err)
+				partial := isPartial(err)
+				if err = c.failure(&errs, err, segment, false); err != nil {
+					return err
+				}
+				if !partial {
+					continue
+				}
 			}
 
 			mapValue.SetMapIndex(keyPtr.Elem(), valPtr.Elem())
@@ -671,109 +731,34 @@ func (c *Converter) convertToMap(destValue, srcValue reflect.Value) error {
 	}
 
 	destValue.Elem().Set(mapValue)
-	return nil
-}
-
-func (c *Converter) convertToStruct(destValue, srcValue reflect.Value) error {
-	destType := destValue.Type().Elem()
-	destInfo := c.getStructInfo(destType)
-
-	var srcMap map[string]interface{}
-
-	switch srcValue.Kind() {
-	case reflect.Map:
-		srcMap = make(map[string]interface{})
-		iter := srcValue.MapRange()
-		for iter.Next() {
-			key := fmt.Sprintf("%v", iter.Key().Interface())
-			srcMap[key] = iter.Value().Interface()
-		}
-	case reflect.Struct:
-		srcInfo := c.getStructInfo(srcValue.Type())
-		srcMap = make(map[string]interface{})
-
-		for _, field := range srcInfo.fields {
-			fieldValue := srcValue.FieldByIndex(field.index)
-
-			var fieldInterface interface{}
-			if fieldValue.CanInterface() {
-				fieldInterface = fieldValue.Interface()
-			} else if c.options.AccessUnexported {
-				// Use unsafe to access unexported fields
-				fieldPtr := reflect.NewAt(fieldValue.Type(), unsafe.Pointer(fieldValue.UnsafeAddr()))
-				fieldInterface = fieldPtr.Elem().Interface()
-			} else {
-				continue
-			}
-
-			srcMap[field.name] = fieldInterface
-		}
-	default:
-		return fmt.Errorf("cannot convert %v to struct", srcValue.Type())
-	}
-
-	for fieldName, fieldInfo := range destInfo.fieldsByName {
-		var srcFieldName string
-
-		// Try by tag name first
-		if fieldInfo.tagName != "" && fieldInfo.tagName != "-" {
-			srcFieldName = fieldInfo.tagName
-			value, exists := srcMap[srcFieldName]
-			if exists {
-				fieldValue := destValue.Elem().FieldByIndex(fieldInfo.index)
-				if setStructField(c, fieldValue, value, fieldInfo.name, fieldName, c.options) {
-					continue
-				}
-			}
-		}
-
-		// Then try by field name if case sensitive
-		if c.options.CaseSensitive {
-			srcFieldName = fieldName
-			value, exists := srcMap[srcFieldName]
-			if exists {
-				fieldValue := destValue.Elem().FieldByIndex(fieldInfo.index)
-				if setStructField(c, fieldValue, value, fieldInfo.name, fieldName, c.options) {
-					continue
-				}
-			}
-		} else {
-			// Or try with case-insensitive field name
-			lowerFieldName := strings.ToLower(fieldName)
-			for key, value := range srcMap {
-				if strings.ToLower(key) == lowerFieldName {
-					fieldValue := destValue.Elem().FieldByIndex(fieldInfo.index)
-					if setStructField(c, fieldValue, value, fieldInfo.name, fieldName, c.options) {
-						break
-					}
-				}
-			}
-		}
-	}
-
-	return nil
+	return errs.err()
 }
 
-func setStructField(c *Converter, fieldValue reflect.Value, value interface{}, fieldName string, structFieldName string, opts Options) bool {
+// setStructField converts value into fieldValue, reporting whether it was assigned.
+// Conversion failures leave the field untouched and are returned to the caller, except for
+// collected nested failures, where the partially converted value is still assigned.
+func setStructField(c *Converter, fieldValue reflect.Value, value interface{}, fieldName string, structFieldName string, opts Options) (bool, error) {
 	if !fieldValue.CanSet() {
 		if opts.AccessUnexported {
 			// For unexported fields using unsafe pointer
 			unsafeFieldPtr := reflect.NewAt(fieldValue.Type(), unsafe.Pointer(fieldValue.UnsafeAddr())).Elem()
 			tempValue := reflect.New(fieldValue.Type())
 
-			if err := c.Convert(value, tempValue.Interface()); err == nil {
-				unsafeFieldPtr.Set(tempValue.Elem())
-				return true
+			err := c.Convert(value, tempValue.Interface())
+			if err != nil && !isPartial(err) {
+				return false, err
 			}
+			unsafeFieldPtr.Set(tempValue.Elem())
+			return true, err
 		}
-		return false
+		return false, nil
 	}
 
 	// Handle nil pointer case for struct pointers
 	if value == nil && fieldValue.Kind() == reflect.Ptr {
 		// If the field is a pointer and the value is nil, set it to nil
 		fieldValue.Set(reflect.Zero(fieldValue.Type()))
-		return true
+		return true, nil
 	}
 
 	// Special handling for nested structs when field is a pointer to struct
@@ -784,107 +769,44 @@ func setStructField(c *Converter, fieldValue reflect.Value, value interface{}, f
 			newStructPtr := reflect.New(fieldValue.Type().Elem())
 
 			// Convert the map to the struct
-			if err := c.Convert(valueMap, newStructPtr.Interface()); err == nil {
-				// Set the pointer to the new struct
-				fieldValue.Set(newStructPtr)
-				return true
+			err := c.Convert(valueMap, newStructPtr.Interface())
+			if err != nil && !isPartial(err) {
+				return false, err
 			}
+			// Set the pointer to the new struct
+			fieldValue.Set(newStructPtr)
+			return true, err
 		}
 	}
 
-	fieldPtr := reflect.New(fieldValue.Type())
-	if err := c.Convert(value, fieldPtr.Interface()); err != nil {
-		return false
-	}
-
-	fieldValue.Set(fieldPtr.Elem())
-	return true
-}
-
-// struct reflection caching
-
-type structField struct {
-	name    string
-	tagName string
-	index   []int
-}
-
-type structInfo struct {
-	fields       []structField
-	fieldsByName map[string]structField
-}
-
-func (c *Converter) getStructInfo(t reflect.Type) *structInfo {
-	if v, ok := c.structCache.Load(t); ok {
-		return v.(*structInfo)
-	}
-
-	info := &structInfo{
-		fields:       make([]structField, 0),
-		fieldsByName: make(map[string]structField),
-	}
-
-	c.buildStructInfo(t, info, nil)
-
-	c.structCache.Store(t, info)
-	return info
-}
-
-func (c *Converter) buildStructInfo(t reflect.Type, info *structInfo, index []int) {
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-
-		fieldIndex := make([]int, len(index)+1)
-		copy(fieldIndex, index)
-		fieldIndex[len(index)] = i
-
-		if field.Anonymous {
-			// Handle embedded fields
-			ft := field.Type
-			if ft.Kind() == reflect.Ptr {
-				ft = ft.Elem()
-			}
-			if ft.Kind() == reflect.Struct {
-				c.buildStructInfo(ft, info, fieldIndex)
-				continue
-			}
-		}
-
-		fieldName := field.Name
-		tagName := ""
-
-		// Check for json tag by default
-		if tag := field.Tag.Get(c.options.TagName); tag != "" {
-			parts := strings.Split(tag, ",")
-			if parts[0] == "-" {
-				tagName = "-" // Mark fields to be skipped
-				// continue - Don't continue here, we need to record this field with tagName "-"
-			} else if parts[0] != "" {
-				tagName = parts[0]
-			}
+	// Pointer fields get a fresh value to convert into, as Convert rejects nil destination pointers
+	if fieldValue.Kind() == reflect.Ptr {
+		rv := reflect.ValueOf(value)
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			fieldValue.Set(reflect.Zero(fieldValue.Type()))
+			return true, nil
 		}
-
-		sf := structField{
-			name:    fieldName,
-			tagName: tagName,
-			index:   fieldIndex,
+		if rv.Type().AssignableTo(fieldValue.Type()) && !opts.ClonePointerData {
+			fieldValue.Set(rv)
+			return true, nil
 		}
-
-		info.fields = append(info.fields, sf)
-
-		name := fieldName
-		if !c.options.CaseSensitive {
-			name = strings.ToLower(name)
+		elemPtr := reflect.New(fieldValue.Type().Elem())
+		err := c.Convert(value, elemPtr.Interface())
+		if err != nil && !isPartial(err) {
+			return false, err
 		}
-		info.fieldsByName[name] = sf
+		fieldValue.Set(elemPtr)
+		return true, err
+	}
 
-		if tagName != "" && tagName != "-" {
-			if !c.options.CaseSensitive {
-				tagName = strings.ToLower(tagName)
-			}
-			info.fieldsByName[tagName] = sf
-		}
+	fieldPtr := reflect.New(fieldValue.Type())
+	err := c.Convert(value, fieldPtr.Interface())
+	if err != nil && !isPartial(err) {
+		return false, err
 	}
+
+	fieldValue.Set(fieldPtr.Elem())
+	return true, err
 }
 
 // helper functions
@@ -59,3 +59,24 @@ func BenchmarkConverter_SliceMapToSliceStruct(b *testing.B) {
     }
 }
 
+
+type benchRow struct {
+    Name       string
+    Age        int64
+    Active     bool
+    Score      float64
+    DateJoined time.Time
+}
+
+func BenchmarkConverter_StructToStruct(b *testing.B) {
+    c := NewConverter(DefaultOptions())
+    src := &benchStruct{Name: "Jane", Age: 42, Active: true, Score: 99.5, DateJoined: time.Now()}
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        var dst benchRow
+        if err := c.Convert(src, &dst); err != nil {
+            b.Fatal(err)
+        }
+    }
+}
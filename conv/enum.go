@@ -0,0 +1,164 @@
+package conv
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+type integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+type enum struct {
+	rType  reflect.Type
+	values map[string]int64
+	lower  map[string]int64
+	labels map[int64]string
+}
+
+// RegisterEnum registers string labels of the enum-like named integer type T. Conversions into T accept
+// labels, matched case-insensitively unless CaseSensitive, or registered values; T converts to strings,
+// and to other registered enums, by label. A value with several labels is written as the first one in
+// lexical order.
+func RegisterEnum[T integer](c *Converter, labels map[string]T) {
+	var zero T
+	e := &enum{
+		rType:  reflect.TypeOf(zero),
+		values: make(map[string]int64, len(labels)),
+		lower:  make(map[string]int64, len(labels)),
+		labels: make(map[int64]string, len(labels)),
+	}
+	names := make([]string, 0, len(labels))
+	for label := range labels {
+		names = append(names, label)
+	}
+	sort.Strings(names)
+	for _, label := range names {
+		number := enumNumber(reflect.ValueOf(labels[label]))
+		e.values[label] = number
+		e.lower[strings.ToLower(label)] = number
+		if _, ok := e.labels[number]; !ok {
+			e.labels[number] = label
+		}
+	}
+	c.enums.Store(e.rType, e)
+}
+
+// enumFor returns the enum registered for rType on c or its ancestors
+func (c *Converter) enumFor(rType reflect.Type) *enum {
+	for ; c != nil; c = c.parent {
+		if v, ok := c.enums.Load(rType); ok {
+			return v.(*enum)
+		}
+	}
+	return nil
+}
+
+func (c *Converter) enumValue(e *enum, label string) (int64, bool) {
+	if value, ok := e.values[label]; ok || c.options.CaseSensitive {
+		return value, ok
+	}
+	value, ok := e.lower[strings.ToLower(label)]
+	return value, ok
+}
+
+// convertNamed handles registered enums, text marshalers and stringers; it reports whether the conversion applied.
+func (c *Converter) convertNamed(destValue, srcValue reflect.Value, src interface{}) (bool, error) {
+	destType := destValue.Type().Elem()
+	srcType := srcValue.Type()
+	named := srcType.PkgPath() != "" && srcType != timeType && srcType != durationType
+	if e := c.enumFor(destType); e != nil {
+		return true, c.convertToEnum(e, destValue, srcValue)
+	}
+	if named && destType.Kind() == reflect.String {
+		var result string
+		if e := c.enumFor(srcType); e != nil {
+			result = e.label(srcValue)
+		} else if marshaler, ok := src.(encoding.TextMarshaler); ok {
+			text, err := marshaler.MarshalText()
+			if err != nil {
+				return true, err
+			}
+			result = string(text)
+		} else if stringer, ok := src.(fmt.Stringer); ok {
+			result = stringer.String()
+		} else {
+			return false, nil
+		}
+		destValue.Elem().SetString(result)
+		return true, nil
+	}
+	if destType.PkgPath() == "" || destType == timeType {
+		return false, nil
+	}
+	var text []byte
+	switch {
+	case srcValue.Kind() == reflect.String:
+		text = []byte(srcValue.String())
+	case srcValue.Kind() == reflect.Slice && srcType.Elem().Kind() == reflect.Uint8:
+		text = srcValue.Bytes()
+	default:
+		return false, nil
+	}
+	unmarshaler, ok := destValue.Interface().(encoding.TextUnmarshaler)
+	if !ok {
+		return false, nil
+	}
+	return true, unmarshaler.UnmarshalText(text)
+}
+
+func (c *Converter) convertToEnum(e *enum, destValue, srcValue reflect.Value) error {
+	var value int64
+	switch srcValue.Kind() {
+	case reflect.String:
+		label := srcValue.String()
+		var ok bool
+		if value, ok = c.enumValue(e, label); !ok {
+			number, err := strconv.ParseInt(strings.TrimSpace(label), 10, 64)
+			if err != nil {
+				return fmt.Errorf("unknown %v label '%s'", e.rType, label)
+			}
+			value = number
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// values of another registered enum are mapped by label
+		if srcEnum := c.enumFor(srcValue.Type()); srcEnum != nil && srcEnum != e {
+			return c.convertToEnum(e, destValue, reflect.ValueOf(srcEnum.label(srcValue)))
+		}
+		value = enumNumber(srcValue)
+	default:
+		return fmt.Errorf("cannot convert %v to %v", srcValue.Type(), e.rType)
+	}
+	if _, ok := e.labels[value]; !ok {
+		return fmt.Errorf("unknown %v value %d", e.rType, value)
+	}
+	if destValue.Elem().CanUint() {
+		destValue.Elem().SetUint(uint64(value))
+	} else {
+		destValue.Elem().SetInt(value)
+	}
+	return nil
+}
+
+// label returns the label of an enum value, or its number when unregistered
+func (e *enum) label(value reflect.Value) string {
+	number := enumNumber(value)
+	if label, ok := e.labels[number]; ok {
+		return label
+	}
+	return strconv.FormatInt(number, 10)
+}
+
+func enumNumber(value reflect.Value) int64 {
+	if value.CanUint() {
+		return int64(value.Uint())
+	}
+	return value.Int()
+}
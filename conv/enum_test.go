@@ -0,0 +1,112 @@
+package conv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type enumStatus int
+
+const (
+	enumStatusActive enumStatus = iota + 1
+	enumStatusSuspended
+)
+
+type enumDBStatus uint8
+
+type enumLevel int
+
+func (l enumLevel) String() string {
+	return strings.Repeat("*", int(l))
+}
+
+type enumCode struct {
+	Prefix string
+	Number string
+}
+
+func (c *enumCode) UnmarshalText(text []byte) error {
+	prefix, number, ok := strings.Cut(string(text), "-")
+	if !ok {
+		return errors.New("invalid code")
+	}
+	c.Prefix, c.Number = prefix, number
+	return nil
+}
+
+func (c enumCode) MarshalText() ([]byte, error) {
+	return []byte(c.Prefix + "-" + c.Number), nil
+}
+
+type enumAccount struct {
+	Status enumStatus
+	Code   enumCode
+	Level  string
+}
+
+func TestConverter_Enums(t *testing.T) {
+	converter := NewConverter(DefaultOptions())
+	RegisterEnum(converter, map[string]enumStatus{"ACTIVE": enumStatusActive, "SUSPENDED": enumStatusSuspended, "ENABLED": enumStatusActive})
+	RegisterEnum(converter, map[string]enumDBStatus{"active": 10, "suspended": 20})
+
+	testCases := []struct {
+		description string
+		src         interface{}
+		expected    enumStatus
+		expectErr   bool
+	}{
+		{description: "label", src: "SUSPENDED", expected: enumStatusSuspended},
+		{description: "case-insensitive label", src: "active", expected: enumStatusActive},
+		{description: "alias", src: "Enabled", expected: enumStatusActive},
+		{description: "value", src: 2, expected: enumStatusSuspended},
+		{description: "numeric string", src: "1", expected: enumStatusActive},
+		{description: "other enum", src: enumDBStatus(20), expected: enumStatusSuspended},
+		{description: "unknown label", src: "DELETED", expectErr: true},
+		{description: "unknown value", src: 7, expectErr: true},
+	}
+	for _, testCase := range testCases {
+		actual, err := To[enumStatus](converter, testCase.src)
+		if testCase.expectErr {
+			assert.Error(t, err, testCase.description)
+			continue
+		}
+		assert.NoError(t, err, testCase.description)
+		assert.Equal(t, testCase.expected, actual, testCase.description)
+	}
+
+	label, err := To[string](converter, enumStatusActive)
+	assert.NoError(t, err)
+	assert.Equal(t, "ACTIVE", label)
+	dbStatus, err := To[enumDBStatus](converter, enumStatusSuspended)
+	assert.NoError(t, err)
+	assert.Equal(t, enumDBStatus(20), dbStatus)
+	number, err := To[int](converter, enumStatusSuspended)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, number)
+
+	strict := DefaultOptions()
+	strict.CaseSensitive = true
+	_, err = To[enumStatus](converter.NewChild(strict), "active")
+	assert.Error(t, err)
+}
+
+func TestConverter_TextAndStringer(t *testing.T) {
+	converter := NewConverter(DefaultOptions())
+	RegisterEnum(converter, map[string]enumStatus{"ACTIVE": enumStatusActive})
+
+	var account enumAccount
+	assert.NoError(t, converter.Convert(map[string]interface{}{"Status": "ACTIVE", "Code": "AB-12", "Level": enumLevel(3)}, &account))
+	assert.Equal(t, enumAccount{Status: enumStatusActive, Code: enumCode{Prefix: "AB", Number: "12"}, Level: "***"}, account)
+
+	values, err := converter.StructToMap(account)
+	assert.NoError(t, err)
+	code, err := To[string](converter, values["Code"])
+	assert.NoError(t, err)
+	assert.Equal(t, "AB-12", code)
+
+	_, err = To[enumCode](converter, "invalid")
+	assert.Error(t, err)
+}
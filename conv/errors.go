@@ -0,0 +1,106 @@
+package conv
+
+import (
+	"errors"
+	"strings"
+)
+
+// ConversionError reports a failed conversion together with the location of the failing value,
+// e.g. Projects[2].Owner.Age
+type ConversionError struct {
+	Path string
+	Err  error
+}
+
+// Error returns the error message prefixed with the path
+func (e *ConversionError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// ConversionErrors holds all failures collected when Options.CollectErrors is enabled
+type ConversionErrors []*ConversionError
+
+// Error returns all collected messages separated by semicolons
+func (e ConversionErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, item := range e {
+		messages[i] = item.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the collected errors
+func (e ConversionErrors) Unwrap() []error {
+	ret := make([]error, len(e))
+	for i, item := range e {
+		ret[i] = item
+	}
+	return ret
+}
+
+// err returns nil when nothing was collected
+func (e ConversionErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// withPath prefixes the path of err (or of every collected error) with segment
+func withPath(err error, segment string) error {
+	switch actual := err.(type) {
+	case ConversionErrors:
+		for _, item := range actual {
+			item.Path = joinPath(segment, item.Path)
+		}
+		return actual
+	case *ConversionError:
+		actual.Path = joinPath(segment, actual.Path)
+		return actual
+	}
+	return &ConversionError{Path: segment, Err: err}
+}
+
+func joinPath(segment, path string) string {
+	if path == "" {
+		return segment
+	}
+	if segment == "" || path[0] == '[' {
+		return segment + path
+	}
+	return segment + "." + path
+}
+
+// isPartial reports whether err holds collected failures of a value that was otherwise converted
+func isPartial(err error) bool {
+	_, ok := err.(ConversionErrors)
+	return ok
+}
+
+// failure annotates err with segment and records it when errors are collected.
+// It returns the error that aborts the conversion, or nil to carry on; skippable
+// failures (unconvertible struct fields) are dropped unless collected or lossy.
+func (c *Converter) failure(errs *ConversionErrors, err error, segment string, skippable bool) error {
+	err = withPath(err, segment)
+	if c.options.CollectErrors {
+		switch actual := err.(type) {
+		case ConversionErrors:
+			*errs = append(*errs, actual...)
+		case *ConversionError:
+			*errs = append(*errs, actual)
+		}
+		return nil
+	}
+	if skippable && !errors.Is(err, ErrLossyConversion) {
+		return nil
+	}
+	return err
+}
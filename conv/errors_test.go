@@ -0,0 +1,91 @@
+package conv
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type errorOwner struct {
+	Name string
+	Age  int
+}
+
+type errorProject struct {
+	Title string
+	Owner *errorOwner
+}
+
+type errorAccount struct {
+	ID       int
+	Projects []errorProject
+	Scores   map[string]int
+}
+
+func TestConverter_ConversionErrorPath(t *testing.T) {
+	src := map[string]interface{}{
+		"ID": 1,
+		"Projects": []interface{}{
+			map[string]interface{}{"Title": "a", "Owner": map[string]interface{}{"Age": 30}},
+			map[string]interface{}{"Title": "b", "Owner": map[string]interface{}{"Age": "x"}},
+		},
+	}
+
+	strict := DefaultOptions()
+	strict.LossPolicy = LossError
+	var account errorAccount
+	err := NewConverter(strict).Convert(map[string]interface{}{"Projects": []interface{}{
+		map[string]interface{}{"Owner": map[string]interface{}{"Age": 1.5}},
+	}}, &account)
+	var conversionErr *ConversionError
+	if assert.True(t, errors.As(err, &conversionErr)) {
+		assert.Equal(t, "Projects[0].Owner.Age", conversionErr.Path)
+		assert.True(t, errors.Is(err, ErrLossyConversion))
+	}
+
+	// unconvertible fields are skipped unless errors are collected
+	account = errorAccount{}
+	assert.NoError(t, NewConverter(DefaultOptions()).Convert(src, &account))
+	assert.Equal(t, 0, account.Projects[1].Owner.Age)
+
+	var items []int
+	err = NewConverter(DefaultOptions()).Convert([]interface{}{1, "x", 3}, &items)
+	if assert.True(t, errors.As(err, &conversionErr)) {
+		assert.Equal(t, "[1]", conversionErr.Path)
+	}
+}
+
+func TestConverter_CollectErrors(t *testing.T) {
+	options := DefaultOptions()
+	options.CollectErrors = true
+	converter := NewConverter(options)
+
+	rows := []interface{}{
+		map[string]interface{}{"ID": 1, "Scores": map[string]interface{}{"math": 5}},
+		map[string]interface{}{"ID": "two", "Scores": map[string]interface{}{"math": "high"}},
+		map[string]interface{}{"ID": 3, "Projects": []interface{}{
+			map[string]interface{}{"Title": "c", "Owner": map[string]interface{}{"Name": "n", "Age": "old"}},
+		}},
+	}
+	var accounts []errorAccount
+	err := converter.Convert(rows, &accounts)
+
+	var collected ConversionErrors
+	if assert.True(t, errors.As(err, &collected)) {
+		var paths []string
+		for _, item := range collected {
+			paths = append(paths, item.Path)
+		}
+		assert.ElementsMatch(t, []string{"[1].ID", "[1].Scores[math]", "[2].Projects[0].Owner.Age"}, paths)
+	}
+	assert.Len(t, accounts, 3)
+	assert.Equal(t, 1, accounts[0].ID)
+	assert.Equal(t, 3, accounts[2].ID)
+	assert.Equal(t, "c", accounts[2].Projects[0].Title)
+
+	var values []int
+	err = converter.Convert([]interface{}{1, "x", 3}, &values)
+	assert.EqualError(t, err, `[1]: strconv.ParseInt: parsing "x": invalid syntax`)
+	assert.Equal(t, []int{1, 0, 3}, values)
+}
@@ -0,0 +1,79 @@
+package conv
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// LossPolicy controls how conversions that cannot preserve the source value are handled
+type LossPolicy int
+
+const (
+	// LossAllow truncates, wraps or saturates values the way Go conversions do (default)
+	LossAllow LossPolicy = iota
+	// LossError rejects overflow, fractional truncation, NaN and out-of-range unix timestamps
+	LossError
+)
+
+// ErrLossyConversion is wrapped by errors reported under LossError
+var ErrLossyConversion = errors.New("lossy conversion")
+
+func lossError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: "+format, append([]interface{}{ErrLossyConversion}, args...)...)
+}
+
+func (c *Converter) strict() bool {
+	return c.options.LossPolicy == LossError
+}
+
+// floatToIntLoss reports why f cannot be represented as an int64
+func floatToIntLoss(f float64) error {
+	switch {
+	case math.IsNaN(f) || math.IsInf(f, 0):
+		return lossError("%v is not a finite number", f)
+	case f != math.Trunc(f):
+		return lossError("%v has a fractional part", f)
+	case f < math.MinInt64 || f >= math.MaxInt64:
+		return lossError("%v overflows int64", f)
+	}
+	return nil
+}
+
+// floatToUintLoss reports why a non-negative f cannot be represented as an uint64
+func floatToUintLoss(f float64) error {
+	switch {
+	case math.IsNaN(f) || math.IsInf(f, 0):
+		return lossError("%v is not a finite number", f)
+	case f != math.Trunc(f):
+		return lossError("%v has a fractional part", f)
+	case f >= math.MaxUint64:
+		return lossError("%v overflows uint64", f)
+	}
+	return nil
+}
+
+// exactInFloat reports whether an integer magnitude fits a float mantissa of the given width
+func exactInFloat(magnitude uint64, mantissa int) bool {
+	if magnitude == 0 {
+		return true
+	}
+	return magnitude>>bits.TrailingZeros64(magnitude) < 1<<mantissa
+}
+
+func floatMantissa(bitSize int) int {
+	if bitSize == 32 {
+		return 24
+	}
+	return 53
+}
+
+// timeRangeLoss rejects numeric timestamps resolving outside of years 1..9999
+func timeRangeLoss(t time.Time, src interface{}) error {
+	if year := t.Year(); year < 1 || year > 9999 {
+		return lossError("unix timestamp %v is out of range", src)
+	}
+	return nil
+}
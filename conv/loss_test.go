@@ -0,0 +1,80 @@
+package conv
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lossRecord struct {
+	Small   int8
+	Count   uint16
+	Ratio   float32
+	Created time.Time
+}
+
+func TestConverter_LossPolicy(t *testing.T) {
+	strict := DefaultOptions()
+	strict.LossPolicy = LossError
+
+	testCases := []struct {
+		description string
+		src         interface{}
+		dest        func() interface{}
+		expectErr   bool
+	}{
+		{description: "int fits", src: 12, dest: func() interface{} { return new(int8) }},
+		{description: "int overflow", src: 300, dest: func() interface{} { return new(int8) }, expectErr: true},
+		{description: "fractional float", src: 3.9, dest: func() interface{} { return new(int) }, expectErr: true},
+		{description: "integral float", src: 4.0, dest: func() interface{} { return new(int) }},
+		{description: "fractional string", src: "3.9", dest: func() interface{} { return new(int) }, expectErr: true},
+		{description: "NaN to int", src: math.NaN(), dest: func() interface{} { return new(int64) }, expectErr: true},
+		{description: "float beyond int64", src: 1e19, dest: func() interface{} { return new(int64) }, expectErr: true},
+		{description: "uint beyond int64", src: uint64(math.MaxUint64), dest: func() interface{} { return new(int64) }, expectErr: true},
+		{description: "uint overflow", src: 70000, dest: func() interface{} { return new(uint16) }, expectErr: true},
+		{description: "fractional uint", src: 1.5, dest: func() interface{} { return new(uint) }, expectErr: true},
+		{description: "float32 overflow", src: 1e300, dest: func() interface{} { return new(float32) }, expectErr: true},
+		{description: "inexact float", src: int64(1<<53 + 1), dest: func() interface{} { return new(float64) }, expectErr: true},
+		{description: "exact float", src: int64(1 << 60), dest: func() interface{} { return new(float64) }},
+		{description: "timestamp", src: int64(1700000000), dest: func() interface{} { return new(time.Time) }},
+		{description: "NaN timestamp", src: math.NaN(), dest: func() interface{} { return new(time.Time) }, expectErr: true},
+		{description: "timestamp out of range", src: int64(-1e12), dest: func() interface{} { return new(time.Time) }, expectErr: true},
+	}
+
+	for _, testCase := range testCases {
+		err := NewConverter(strict).Convert(testCase.src, testCase.dest())
+		if testCase.expectErr {
+			assert.True(t, errors.Is(err, ErrLossyConversion), testCase.description)
+			continue
+		}
+		assert.NoError(t, err, testCase.description)
+		assert.NoError(t, NewConverter(DefaultOptions()).Convert(testCase.src, testCase.dest()), testCase.description)
+	}
+}
+
+func TestConverter_LossPolicyField(t *testing.T) {
+	strict := DefaultOptions()
+	strict.LossPolicy = LossError
+
+	var record lossRecord
+	err := NewConverter(strict).Convert(map[string]interface{}{"Small": 300, "Count": 2}, &record)
+	assert.True(t, errors.Is(err, ErrLossyConversion))
+	var conversionErr *ConversionError
+	if assert.True(t, errors.As(err, &conversionErr)) {
+		assert.Equal(t, "Small", conversionErr.Path)
+	}
+
+	err = NewConverter(strict).Convert(struct{ Ratio float64 }{Ratio: math.MaxFloat64}, &record)
+	if assert.True(t, errors.As(err, &conversionErr)) {
+		assert.Equal(t, "Ratio", conversionErr.Path)
+	}
+
+	record = lossRecord{}
+	err = NewConverter(DefaultOptions()).Convert(map[string]interface{}{"Small": 300, "Count": 2.7, "Created": math.NaN()}, &record)
+	assert.NoError(t, err)
+	assert.Equal(t, int8(44), record.Small)
+	assert.Equal(t, uint16(2), record.Count)
+}
@@ -0,0 +1,281 @@
+package conv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// MappingTag is the struct tag holding field mapping rules, e.g. `conv:"from=Address.City,default=n/a"`
+const MappingTag = "conv"
+
+// FieldMapping describes how a destination field is populated in place of name matching
+type FieldMapping struct {
+	// Field is the destination field path, e.g. Address.City
+	Field string
+	// From is the source field or key path, e.g. Location.City; "." selects the whole source value.
+	// It defaults to the destination field name.
+	From string
+	// Default is used when the source path is missing, unset by a source `setMarker` or nil; zero values are assigned as is
+	Default interface{}
+	// Const, when set, is assigned regardless of the source
+	Const interface{}
+}
+
+type mappingRule struct {
+	dest []string
+	// from is nil when the rule reads the whole source value
+	from []string
+	// alias is tried when from is missing; tag rules without from= look up the tag name, then the field name
+	alias      []string
+	value      interface{}
+	hasDefault bool
+	isConst    bool
+}
+
+// RegisterMapping registers field mappings for conversions from srcType to destType;
+// mappings registered for the same destination field override earlier ones and `conv` tags.
+func (c *Converter) RegisterMapping(srcType, destType reflect.Type, mappings ...FieldMapping) error {
+	for _, mapping := range mappings {
+		if err := c.validateDestPath(destType, splitPath(mapping.Field)); err != nil {
+			return fmt.Errorf("invalid mapping %v -> %v: %w", srcType, destType, err)
+		}
+	}
+	key := typeKey{srcType, destType}
+	var registered []FieldMapping
+	if v, ok := c.mappings.Load(key); ok {
+		registered = v.([]FieldMapping)
+	}
+	c.mappings.Store(key, append(append([]FieldMapping{}, registered...), mappings...))
+	c.planCache.Clear()
+	c.generation.Add(1)
+	return nil
+}
+
+// parseMappingTag parses a `conv` tag; it returns nil when the tag has no rule.
+func parseMappingTag(tag string, fieldName, tagName string) *mappingRule {
+	if tag == "" {
+		return nil
+	}
+	rule := &mappingRule{dest: []string{fieldName}, from: []string{fieldName}}
+	if tagName != "" && tagName != "-" {
+		rule.from, rule.alias = []string{tagName}, rule.from
+	}
+	for _, part := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "from":
+			rule.from, rule.alias = splitPath(value), nil
+		case "default":
+			rule.value, rule.hasDefault = value, true
+		case "const":
+			rule.value, rule.isConst = value, true
+		}
+	}
+	return rule
+}
+
+func newMappingRule(mapping FieldMapping) *mappingRule {
+	rule := &mappingRule{dest: splitPath(mapping.Field), from: splitPath(mapping.From)}
+	if mapping.From == "" {
+		rule.from = rule.dest[len(rule.dest)-1:]
+	}
+	switch {
+	case mapping.Const != nil:
+		rule.value, rule.isConst = mapping.Const, true
+	case mapping.Default != nil:
+		rule.value, rule.hasDefault = mapping.Default, true
+	}
+	return rule
+}
+
+// splitPath splits a dotted path; "." yields nil, the whole value.
+func splitPath(path string) []string {
+	if path == "." || path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+// registeredMappings returns mappings of the ancestors of c followed by its own, so the latter take precedence.
+func (c *Converter) registeredMappings(key typeKey) []FieldMapping {
+	var ret []FieldMapping
+	if c.parent != nil {
+		ret = c.parent.registeredMappings(key)
+	}
+	if v, ok := c.mappings.Load(key); ok {
+		ret = append(ret, v.([]FieldMapping)...)
+	}
+	return ret
+}
+
+// compileRules returns tag rules of destInfo followed by registered mappings for the type pair.
+func (c *Converter) compileRules(srcType, destType reflect.Type, destInfo *structInfo) []*mappingRule {
+	var rules []*mappingRule
+	for _, field := range destInfo.bindable {
+		if field.rule != nil && (field.exported || c.options.AccessUnexported) {
+			rules = append(rules, field.rule)
+		}
+	}
+	for _, mapping := range c.registeredMappings(typeKey{srcType, destType}) {
+		rules = append(rules, newMappingRule(mapping))
+	}
+	return rules
+}
+
+func (c *Converter) validateDestPath(destType reflect.Type, path []string) error {
+	if len(path) == 0 {
+		return fmt.Errorf("destination field is required")
+	}
+	for i, name := range path {
+		for destType.Kind() == reflect.Ptr {
+			destType = destType.Elem()
+		}
+		if destType.Kind() != reflect.Struct {
+			return fmt.Errorf("%s is not a struct", strings.Join(path[:i], "."))
+		}
+		field := c.getStructInfo(destType).fieldsByName[c.fieldKey(name)]
+		if field == nil {
+			return fmt.Errorf("unknown field %s", strings.Join(path[:i+1], "."))
+		}
+		destType = field.rType
+	}
+	return nil
+}
+
+// applyRules assigns mapped fields of the struct at destPtr from srcValue.
+func (c *Converter) applyRules(destPtr unsafe.Pointer, plan *structPlan, srcValue reflect.Value) error {
+	var errs ConversionErrors
+	for _, rule := range plan.rules {
+		value, ok := c.ruleValue(rule, srcValue)
+		if !ok {
+			continue
+		}
+		if err := c.assignPath(destPtr, plan.destInfo, rule.dest, value); err != nil {
+			if err = c.failure(&errs, err, "", true); err != nil {
+				return err
+			}
+		}
+	}
+	return errs.err()
+}
+
+func (c *Converter) ruleValue(rule *mappingRule, srcValue reflect.Value) (interface{}, bool) {
+	if rule.isConst {
+		return rule.value, true
+	}
+	if rule.from == nil {
+		return srcValue.Interface(), true
+	}
+	if value, ok := c.sourceValue(srcValue, rule.from); ok {
+		return value, true
+	}
+	if rule.alias != nil {
+		if value, ok := c.sourceValue(srcValue, rule.alias); ok {
+			return value, true
+		}
+	}
+	return rule.value, rule.hasDefault
+}
+
+// sourceValue resolves a path of struct fields or string map keys; fields unset by a marker and nil values count as missing.
+func (c *Converter) sourceValue(value reflect.Value, path []string) (interface{}, bool) {
+	for _, name := range path {
+		for value.Kind() == reflect.Interface || value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return nil, false
+			}
+			value = value.Elem()
+		}
+		switch value.Kind() {
+		case reflect.Struct:
+			info := c.getStructInfo(value.Type())
+			field := info.fieldsByName[c.fieldKey(name)]
+			if field == nil || !field.exported && !c.options.AccessUnexported {
+				return nil, false
+			}
+			if !value.CanAddr() {
+				copied := reflect.New(value.Type())
+				copied.Elem().Set(value)
+				value = copied.Elem()
+			}
+			structPtr := value.Addr().UnsafePointer()
+			if marker := info.marker; marker != nil && field.markerIndex != -1 && marker.CanUseHolder(structPtr) && !marker.IsSet(structPtr, field.markerIndex) {
+				return nil, false
+			}
+			ptr := field.pointer(structPtr, false)
+			if ptr == nil {
+				return nil, false
+			}
+			value = reflect.NewAt(field.rType, ptr).Elem()
+		case reflect.Map:
+			if value.Type().Key().Kind() != reflect.String {
+				return nil, false
+			}
+			if value = c.mapEntry(value, name); !value.IsValid() {
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+	}
+	if !value.IsValid() {
+		return nil, false
+	}
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if value.IsNil() {
+			return nil, false
+		}
+	}
+	return value.Interface(), true
+}
+
+func (c *Converter) mapEntry(value reflect.Value, name string) reflect.Value {
+	key := reflect.ValueOf(name).Convert(value.Type().Key())
+	if entry := value.MapIndex(key); entry.IsValid() || c.options.CaseSensitive {
+		return entry
+	}
+	iter := value.MapRange()
+	for iter.Next() {
+		if strings.EqualFold(iter.Key().String(), name) {
+			return iter.Value()
+		}
+	}
+	return reflect.Value{}
+}
+
+// assignPath sets the field at path within the struct at ptr, allocating nested pointers on the way.
+func (c *Converter) assignPath(ptr unsafe.Pointer, info *structInfo, path []string, value interface{}) error {
+	field := info.fieldsByName[c.fieldKey(path[0])]
+	if field == nil {
+		return fmt.Errorf("unknown field %s", path[0])
+	}
+	fieldValue := reflect.NewAt(field.rType, field.pointer(ptr, true)).Elem()
+	if len(path) == 1 {
+		assigned, err := setStructField(c, fieldValue, value, field.name, field.name, c.options)
+		if assigned {
+			c.markAssigned(info, ptr, field)
+		}
+		if err != nil {
+			return withPath(err, field.name)
+		}
+		return nil
+	}
+	if fieldValue.Kind() == reflect.Ptr {
+		if fieldValue.IsNil() {
+			fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+		}
+		fieldValue = fieldValue.Elem()
+	}
+	if fieldValue.Kind() != reflect.Struct {
+		return fmt.Errorf("%s is not a struct", field.name)
+	}
+	err := c.assignPath(fieldValue.Addr().UnsafePointer(), c.getStructInfo(fieldValue.Type()), path[1:], value)
+	c.markAssigned(info, ptr, field)
+	if err != nil {
+		return withPath(err, field.name)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package conv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mappingAddress struct {
+	Street string
+	City   string
+}
+
+type mappingCustomer struct {
+	ID       int
+	FullName string
+	Address  *mappingAddress
+}
+
+type mappingCustomerRow struct {
+	ID      int    `json:"id"`
+	Name    string `conv:"from=FullName"`
+	City    string `conv:"from=Address.City,default=unknown"`
+	Street  string `conv:"from=Address.Street"`
+	Source  string `conv:"const=crm"`
+	Country string `json:"country" conv:"default=US"`
+}
+
+type mappingAddressView struct {
+	Street string
+	City   string
+}
+
+type mappingCustomerView struct {
+	ID       int
+	FullName string             `conv:"from=Name"`
+	Address  mappingAddressView `conv:"from=."`
+}
+
+func TestConverter_MappingTags(t *testing.T) {
+	converter := NewConverter(DefaultOptions())
+
+	var row mappingCustomerRow
+	assert.NoError(t, converter.Convert(&mappingCustomer{ID: 7, FullName: "Ann", Address: &mappingAddress{Street: "Main", City: "Oslo"}}, &row))
+	assert.Equal(t, mappingCustomerRow{ID: 7, Name: "Ann", City: "Oslo", Street: "Main", Source: "crm", Country: "US"}, row)
+
+	row = mappingCustomerRow{}
+	assert.NoError(t, converter.Convert(mappingCustomer{ID: 8, FullName: "Bob"}, &row))
+	assert.Equal(t, mappingCustomerRow{ID: 8, Name: "Bob", City: "unknown", Source: "crm", Country: "US"}, row)
+
+	row = mappingCustomerRow{}
+	assert.NoError(t, converter.Convert(map[string]interface{}{
+		"id": 9, "FullName": "Cy", "country": "PL", "Source": "ignored",
+		"address": map[string]interface{}{"city": "Krakow"},
+	}, &row))
+	assert.Equal(t, mappingCustomerRow{ID: 9, Name: "Cy", City: "Krakow", Source: "crm", Country: "PL"}, row)
+
+	var view mappingCustomerView
+	assert.NoError(t, converter.Convert(mappingCustomerRow{ID: 7, Name: "Ann", City: "Oslo", Street: "Main"}, &view))
+	assert.Equal(t, mappingCustomerView{ID: 7, FullName: "Ann", Address: mappingAddressView{Street: "Main", City: "Oslo"}}, view)
+}
+
+type mappingCustomerDTO struct {
+	Key     string
+	Name    string
+	Address mappingAddress
+	Version int
+}
+
+func TestConverter_RegisterMapping(t *testing.T) {
+	converter := NewConverter(DefaultOptions())
+	srcType := reflect.TypeOf(mappingCustomerRow{})
+	destType := reflect.TypeOf(mappingCustomerDTO{})
+
+	// plans compiled before registration are replaced
+	var dto mappingCustomerDTO
+	assert.NoError(t, converter.Convert(mappingCustomerRow{Name: "Ann"}, &dto))
+	assert.Equal(t, "Ann", dto.Name)
+
+	assert.NoError(t, converter.RegisterMapping(srcType, destType,
+		FieldMapping{Field: "Key", From: "ID"},
+		FieldMapping{Field: "Address.City", From: "City"},
+		FieldMapping{Field: "Address.Street", From: "Street", Default: "n/a"},
+		FieldMapping{Field: "Version", Const: 2},
+	))
+	// a zero source value is present, so the default does not apply
+	dto = mappingCustomerDTO{}
+	assert.NoError(t, converter.Convert(mappingCustomerRow{ID: 3, Name: "Ann", City: "Oslo"}, &dto))
+	assert.Equal(t, mappingCustomerDTO{Key: "3", Name: "Ann", Address: mappingAddress{City: "Oslo"}, Version: 2}, dto)
+
+	assert.Error(t, converter.RegisterMapping(srcType, destType, FieldMapping{Field: "Address.Zip"}))
+	assert.Error(t, converter.RegisterMapping(srcType, destType, FieldMapping{Field: "Key.Value"}))
+}
+
+type mappingFlagsHas struct {
+	Active bool
+	Name   bool
+}
+
+type mappingFlags struct {
+	Active bool
+	Name   string
+	Has    *mappingFlagsHas `setMarker:"true"`
+}
+
+type mappingFlagsRowHas struct {
+	Enabled bool
+	Label   bool
+}
+
+type mappingFlagsRow struct {
+	Enabled bool                `conv:"from=Active"`
+	Label   string              `conv:"from=Name,default=none"`
+	Has     *mappingFlagsRowHas `setMarker:"true"`
+}
+
+func TestConverter_MappingZeroAndMarkers(t *testing.T) {
+	converter := NewConverter(DefaultOptions())
+
+	row := mappingFlagsRow{Enabled: true, Label: "old"}
+	assert.NoError(t, converter.Convert(mappingFlags{Name: "x", Has: &mappingFlagsHas{Active: true}}, &row))
+	assert.False(t, row.Enabled)
+	assert.Equal(t, "none", row.Label)
+	assert.True(t, row.Has.Enabled)
+	assert.True(t, row.Has.Label)
+
+	row = mappingFlagsRow{Enabled: true}
+	assert.NoError(t, converter.Convert(mappingFlags{Name: "x"}, &row))
+	assert.False(t, row.Enabled)
+	assert.Equal(t, "x", row.Label)
+}
@@ -0,0 +1,137 @@
+package conv
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/viant/tagly/format/text"
+)
+
+// StructToMap converts a struct, or a pointer to one, into a map keyed the way MapToStruct reads it:
+// by the TagName tag, otherwise by the field name formatted with CaseFormat. Nested structs, slices
+// and maps are converted recursively, except for time.Time and text marshalers; zero omitempty fields are left out, and so are fields not flagged
+// by a setMarker holder when OnlySetFields is enabled.
+func (c *Converter) StructToMap(src interface{}) (map[string]interface{}, error) {
+	value := indirect(reflect.ValueOf(src))
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot convert %T to map: expected struct", src)
+	}
+	return c.structToMap(value), nil
+}
+
+// MapToStruct populates dest, a pointer to a struct, from src using the key rules of StructToMap.
+func (c *Converter) MapToStruct(src map[string]interface{}, dest interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() || indirect(destValue).Kind() != reflect.Struct {
+		return fmt.Errorf("cannot convert map to %T: expected pointer to struct", dest)
+	}
+	return c.Convert(src, dest)
+}
+
+func (c *Converter) structToMap(value reflect.Value) map[string]interface{} {
+	if !value.CanAddr() {
+		copied := reflect.New(value.Type())
+		copied.Elem().Set(value)
+		value = copied.Elem()
+	}
+	ptr := value.Addr().UnsafePointer()
+	info := c.getStructInfo(value.Type())
+	marker := info.marker
+	if !c.options.OnlySetFields || marker != nil && !marker.CanUseHolder(ptr) {
+		marker = nil
+	}
+	ret := make(map[string]interface{}, len(info.bindable))
+	for _, field := range info.bindable {
+		if field.tagName == "-" || !field.exported && !c.options.AccessUnexported {
+			continue
+		}
+		if marker != nil && field.markerIndex != -1 && !marker.IsSet(ptr, field.markerIndex) {
+			continue
+		}
+		fieldPtr := field.pointer(ptr, false)
+		if fieldPtr == nil {
+			continue
+		}
+		fieldValue := reflect.NewAt(field.rType, fieldPtr).Elem()
+		if field.omitEmpty && fieldValue.IsZero() {
+			continue
+		}
+		ret[field.key] = c.mapValue(fieldValue)
+	}
+	return ret
+}
+
+// mapValue converts nested structs into maps, and slices or maps holding them into generic collections.
+func (c *Converter) mapValue(value reflect.Value) interface{} {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return c.mapValue(value.Elem())
+	case reflect.Struct:
+		if isLeafStruct(value.Type()) {
+			return value.Interface()
+		}
+		return c.structToMap(value)
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() || !nestsStruct(value.Type().Elem()) {
+			return value.Interface()
+		}
+		ret := make([]interface{}, value.Len())
+		for i := range ret {
+			ret[i] = c.mapValue(value.Index(i))
+		}
+		return ret
+	case reflect.Map:
+		if value.IsNil() || !nestsStruct(value.Type().Elem()) {
+			return value.Interface()
+		}
+		ret := make(map[string]interface{}, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			ret[fmt.Sprintf("%v", iter.Key().Interface())] = c.mapValue(iter.Value())
+		}
+		return ret
+	}
+	return value.Interface()
+}
+
+// nestsStruct reports whether values of rType may hold structs to convert into maps.
+func nestsStruct(rType reflect.Type) bool {
+	for {
+		switch rType.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			rType = rType.Elem()
+		case reflect.Struct:
+			return !isLeafStruct(rType)
+		case reflect.Interface:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// isLeafStruct reports whether struct values of rType are kept as they are rather than converted into maps
+func isLeafStruct(rType reflect.Type) bool {
+	return rType == timeType || rType.Implements(textMarshalerType) || reflect.PointerTo(rType).Implements(textMarshalerType)
+}
+
+// formatName formats an untagged field name with CaseFormat.
+func (c *Converter) formatName(name string) string {
+	if c.options.CaseFormat == "" {
+		return name
+	}
+	if name == "ID" {
+		switch c.options.CaseFormat {
+		case text.CaseFormatLower, text.CaseFormatLowerCamel, text.CaseFormatLowerUnderscore:
+			return "id"
+		}
+	}
+	src := text.DetectCaseFormat(name)
+	if !src.IsDefined() {
+		src = text.CaseFormatUpperCamel
+	}
+	return src.Format(name, c.options.CaseFormat)
+}
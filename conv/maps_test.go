@@ -0,0 +1,66 @@
+package conv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/tagly/format/text"
+)
+
+type mapsLine struct {
+	SKU      string
+	Quantity int `json:"qty,omitempty"`
+}
+
+type mapsOrderHas struct {
+	OrderID bool
+	Note    bool
+	Lines   bool
+}
+
+type mapsOrder struct {
+	OrderID  int `json:"id"`
+	Note     *string
+	Created  time.Time
+	Lines    []*mapsLine
+	Tags     []string
+	Internal string        `json:"-"`
+	Has      *mapsOrderHas `setMarker:"true"`
+}
+
+func TestConverter_StructToMap(t *testing.T) {
+	options := DefaultOptions()
+	options.CaseFormat = text.CaseFormatLowerUnderscore
+	converter := NewConverter(options)
+
+	note := "rush"
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	order := &mapsOrder{OrderID: 1, Note: &note, Created: created, Lines: []*mapsLine{{SKU: "a", Quantity: 2}, {SKU: "b"}}, Tags: []string{"x"}, Internal: "secret"}
+
+	actual, err := converter.StructToMap(order)
+	assert.NoError(t, err)
+	expected := map[string]interface{}{
+		"id":      1,
+		"note":    "rush",
+		"created": created,
+		"lines":   []interface{}{map[string]interface{}{"sku": "a", "qty": 2}, map[string]interface{}{"sku": "b"}},
+		"tags":    []string{"x"},
+	}
+	assert.Equal(t, expected, actual)
+
+	var restored mapsOrder
+	assert.NoError(t, converter.MapToStruct(actual, &restored))
+	assert.Equal(t, &mapsOrderHas{OrderID: true, Note: true, Lines: true}, restored.Has)
+	restored.Has, order.Internal = nil, ""
+	assert.Equal(t, order, &restored)
+
+	options.OnlySetFields = true
+	actual, err = NewConverter(options).StructToMap(mapsOrder{OrderID: 2, Note: &note, Has: &mapsOrderHas{Note: true}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"note": "rush"}, actual)
+
+	_, err = converter.StructToMap(map[string]interface{}{})
+	assert.Error(t, err)
+	assert.Error(t, converter.MapToStruct(actual, restored))
+}
@@ -0,0 +1,89 @@
+package conv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type markerAddressHas struct {
+	City bool
+	Zip  bool
+}
+
+type markerAddress struct {
+	City string
+	Zip  string
+	Has  *markerAddressHas `setMarker:"true"`
+}
+
+type markerUserDTOHas struct {
+	ID      bool
+	Name    bool
+	Email   bool
+	Address bool
+}
+
+type markerUserDTO struct {
+	ID      int
+	Name    string
+	Email   string
+	Address *markerAddress
+	Has     *markerUserDTOHas `setMarker:"true"`
+}
+
+type markerUserHas struct {
+	ID      bool
+	Name    bool
+	Email   bool
+	Address bool
+}
+
+type markerUser struct {
+	ID      int64
+	Name    string
+	Email   string
+	Address *markerAddress
+	Has     markerUserHas `setMarker:"true"`
+}
+
+type markerUserRow struct {
+	ID    int64
+	Name  string
+	Email string
+}
+
+func TestConverter_MarkerAwareStruct(t *testing.T) {
+	converter := NewConverter(DefaultOptions())
+	dto := &markerUserDTO{
+		ID:      7,
+		Name:    "",
+		Email:   "ignored@x.com",
+		Address: &markerAddress{City: "Paris", Has: &markerAddressHas{City: true}},
+		Has:     &markerUserDTOHas{ID: true, Name: true, Address: true},
+	}
+
+	user := markerUser{Name: "old", Email: "keep@x.com"}
+	assert.NoError(t, converter.Convert(dto, &user))
+	assert.EqualValues(t, 7, user.ID)
+	assert.Equal(t, "", user.Name)
+	assert.Equal(t, "keep@x.com", user.Email)
+	assert.Equal(t, markerUserHas{ID: true, Name: true, Address: true}, user.Has)
+	assert.Equal(t, "Paris", user.Address.City)
+	assert.Equal(t, &markerAddressHas{City: true}, user.Address.Has)
+
+	row := markerUserRow{Email: "db@x.com"}
+	assert.NoError(t, converter.Convert(user, &row))
+	assert.Equal(t, markerUserRow{ID: 7, Email: "db@x.com"}, row)
+
+	// without a marker holder every field counts as set
+	dto.Has = nil
+	user = markerUser{}
+	assert.NoError(t, converter.Convert(dto, &user))
+	assert.Equal(t, "ignored@x.com", user.Email)
+	assert.Equal(t, markerUserHas{ID: true, Name: true, Email: true, Address: true}, user.Has)
+
+	var fromMap markerUser
+	assert.NoError(t, converter.Convert(map[string]interface{}{"name": "m"}, &fromMap))
+	assert.Equal(t, markerUserHas{Name: true}, fromMap.Has)
+}
@@ -0,0 +1,416 @@
+package conv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+
+	"github.com/viant/structology"
+	"github.com/viant/xunsafe"
+)
+
+// struct reflection caching
+
+type structField struct {
+	name     string
+	tagName  string
+	index    []int
+	rType    reflect.Type
+	exported bool
+	// path holds one accessor per index step; embedded pointers are dereferenced between steps
+	path []*xunsafe.Field
+	// markerIndex is the marker position of a top-level field, or -1
+	markerIndex int
+	// rule is the `conv` tag mapping of the field, if any
+	rule *mappingRule
+	// key is the map key of the field: its tag name, otherwise its name formatted with CaseFormat
+	key       string
+	omitEmpty bool
+	// valuesKey is the `form` or `query` tag name used by FromValues and ToValues, if any
+	valuesKey       string
+	valuesOmitEmpty bool
+	// valuesSplit enables splitting comma separated values of a slice field (`form:"ids,split"`)
+	valuesSplit bool
+}
+
+type structInfo struct {
+	fields       []*structField
+	fieldsByName map[string]*structField
+	// bindable lists fields reachable by name or tag, in declaration order, without shadowed embedded fields
+	bindable []*structField
+	// marker tracks field presence when the struct has a `setMarker` holder; the holder itself is not a field.
+	marker *structology.Marker
+}
+
+// structPlan binds source to destination fields once per type pair.
+type structPlan struct {
+	// convertible marks same-layout structs converted as a whole value
+	convertible bool
+	srcInfo     *structInfo
+	destInfo    *structInfo
+	pairs       []fieldPair
+	// rules are applied after pairs; mapped destination fields are excluded from name matching
+	rules  []*mappingRule
+	mapped map[*structField]bool
+}
+
+type fieldPair struct {
+	src  *structField
+	dest *structField
+	// direct pairs share a type and are copied without boxing the value
+	direct bool
+}
+
+func (c *Converter) getStructInfo(t reflect.Type) *structInfo {
+	if v, ok := c.structCache.Load(t); ok {
+		return v.(*structInfo)
+	}
+
+	info := &structInfo{
+		fields:       make([]*structField, 0),
+		fieldsByName: make(map[string]*structField),
+	}
+
+	if structology.HasSetMarker(t) {
+		if marker, err := structology.NewMarker(t, structology.WithNoStrict(true)); err == nil {
+			info.marker = marker
+		}
+	}
+	c.buildStructInfo(t, info, nil, nil)
+	for _, field := range info.fields {
+		if info.fieldsByName[c.fieldKey(field.name)] == field ||
+			field.tagName != "" && field.tagName != "-" && info.fieldsByName[c.fieldKey(field.tagName)] == field {
+			info.bindable = append(info.bindable, field)
+		}
+	}
+
+	v, _ := c.structCache.LoadOrStore(t, info)
+	return v.(*structInfo)
+}
+
+func (c *Converter) buildStructInfo(t reflect.Type, info *structInfo, index []int, path []*xunsafe.Field) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+		fieldPath := append(append(make([]*xunsafe.Field, 0, len(path)+1), path...), xunsafe.NewField(field))
+
+		if structology.IsSetMarker(field.Tag) {
+			continue
+		}
+
+		if field.Anonymous {
+			// Handle embedded fields
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				c.buildStructInfo(ft, info, fieldIndex, fieldPath)
+				continue
+			}
+		}
+
+		fieldName := field.Name
+		tagName := ""
+		omitEmpty := false
+
+		// Check for json tag by default
+		if tag := field.Tag.Get(c.options.TagName); tag != "" {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				tagName = "-" // Mark fields to be skipped
+				// continue - Don't continue here, we need to record this field with tagName "-"
+			} else if parts[0] != "" {
+				tagName = parts[0]
+			}
+			for _, part := range parts[1:] {
+				omitEmpty = omitEmpty || strings.TrimSpace(part) == "omitempty"
+			}
+		}
+
+		sf := &structField{
+			name:        fieldName,
+			tagName:     tagName,
+			index:       fieldIndex,
+			rType:       field.Type,
+			exported:    field.IsExported(),
+			path:        fieldPath,
+			markerIndex: -1,
+			rule:        parseMappingTag(field.Tag.Get(MappingTag), fieldName, tagName),
+			key:         tagName,
+			omitEmpty:   omitEmpty,
+		}
+		sf.valuesKey, sf.valuesOmitEmpty, sf.valuesSplit = valuesTagName(field.Tag)
+		if tagName == "" || tagName == "-" {
+			sf.key = c.formatName(fieldName)
+		}
+		if len(fieldIndex) == 1 {
+			sf.markerIndex = info.marker.Index(fieldName)
+		}
+
+		info.fields = append(info.fields, sf)
+		info.fieldsByName[c.fieldKey(fieldName)] = sf
+
+		if tagName != "" && tagName != "-" {
+			info.fieldsByName[c.fieldKey(tagName)] = sf
+		} else if sf.key != fieldName {
+			info.fieldsByName[c.fieldKey(sf.key)] = sf
+		}
+	}
+}
+
+func (c *Converter) fieldKey(name string) string {
+	if c.options.CaseSensitive {
+		return name
+	}
+	return strings.ToLower(name)
+}
+
+// pointer returns the field address within the struct at ptr; nil embedded pointers are allocated when alloc is set,
+// otherwise nil is returned.
+func (f *structField) pointer(ptr unsafe.Pointer, alloc bool) unsafe.Pointer {
+	last := len(f.path) - 1
+	for i, field := range f.path {
+		ptr = field.Pointer(ptr)
+		if i == last || field.Kind() != reflect.Ptr {
+			continue
+		}
+		next := *(*unsafe.Pointer)(ptr)
+		if next == nil {
+			if !alloc {
+				return nil
+			}
+			next = reflect.New(field.Type.Elem()).UnsafePointer()
+			*(*unsafe.Pointer)(ptr) = next
+		}
+		ptr = next
+	}
+	return ptr
+}
+
+func (c *Converter) structPlanFor(srcType, destType reflect.Type) *structPlan {
+	key := typeKey{srcType: srcType, destType: destType}
+	if c.parent != nil {
+		// plans depend on inherited registrations, which may change after the child was created
+		if generation := c.lineageGeneration(); generation != c.planGeneration.Load() {
+			c.planCache.Clear()
+			c.planGeneration.Store(generation)
+		}
+	}
+	if v, ok := c.planCache.Load(key); ok {
+		return v.(*structPlan)
+	}
+	plan := c.compileStructPlan(srcType, destType)
+	v, _ := c.planCache.LoadOrStore(key, plan)
+	return v.(*structPlan)
+}
+
+// compileStructPlan matches each destination field to a source field name by
+// exact tag, exact name, then (unless CaseSensitive) case-insensitive tag and name.
+// Map sources only compile mapping rules.
+func (c *Converter) compileStructPlan(srcType, destType reflect.Type) *structPlan {
+	plan := &structPlan{destInfo: c.getStructInfo(destType), mapped: map[*structField]bool{}}
+	plan.rules = c.compileRules(srcType, destType, plan.destInfo)
+	for _, rule := range plan.rules {
+		if len(rule.dest) == 1 {
+			plan.mapped[plan.destInfo.fieldsByName[c.fieldKey(rule.dest[0])]] = true
+		}
+	}
+	if srcType.Kind() != reflect.Struct {
+		return plan
+	}
+	srcInfo := c.getStructInfo(srcType)
+	plan.srcInfo = srcInfo
+	// sources with presence markers take the field-by-field path so unset fields are not copied
+	if srcInfo.marker == nil && len(plan.rules) == 0 && srcType.ConvertibleTo(destType) {
+		plan.convertible = true
+		return plan
+	}
+
+	byName := map[string]*structField{}
+	byLower := map[string]*structField{}
+	for _, field := range srcInfo.fields {
+		if !field.exported && !c.options.AccessUnexported {
+			continue
+		}
+		byName[field.name] = field
+		byLower[strings.ToLower(field.name)] = field
+	}
+	for _, dest := range plan.destInfo.bindable {
+		if !dest.exported && !c.options.AccessUnexported || plan.mapped[dest] {
+			continue
+		}
+		src := c.matchField(dest, func(name string, exact bool) *structField {
+			if exact {
+				return byName[name]
+			}
+			return byLower[strings.ToLower(name)]
+		})
+		if src == nil {
+			continue
+		}
+		pair := fieldPair{src: src, dest: dest}
+		if src.rType == dest.rType && !(c.options.ClonePointerData && src.rType.Kind() == reflect.Ptr) {
+			_, custom := c.conversion(typeKey{src.rType, dest.rType})
+			pair.direct = !custom
+		}
+		plan.pairs = append(plan.pairs, pair)
+	}
+	return plan
+}
+
+// matchField resolves the source of dest through lookup, trying exact names before case-insensitive ones.
+// The map key (tag name or case-formatted name) takes precedence over the field name.
+func (c *Converter) matchField(dest *structField, lookup func(name string, exact bool) *structField) *structField {
+	hasKey := dest.key != "" && dest.key != dest.name
+	if hasKey {
+		if src := lookup(dest.key, true); src != nil {
+			return src
+		}
+	}
+	if src := lookup(dest.name, true); src != nil {
+		return src
+	}
+	if c.options.CaseSensitive {
+		return nil
+	}
+	if hasKey {
+		if src := lookup(dest.key, false); src != nil {
+			return src
+		}
+	}
+	return lookup(dest.name, false)
+}
+
+func (c *Converter) convertToStruct(destValue, srcValue reflect.Value) error {
+	destType := destValue.Type().Elem()
+	destPtr := destValue.UnsafePointer()
+
+	var err error
+	plan := c.structPlanFor(srcValue.Type(), destType)
+	switch srcValue.Kind() {
+	case reflect.Map:
+		err = c.assignFromMap(destPtr, plan, srcValue)
+	case reflect.Struct:
+		err = c.assignFromStruct(destPtr, plan, srcValue)
+	default:
+		return fmt.Errorf("cannot convert %v to struct", srcValue.Type())
+	}
+	if len(plan.rules) == 0 || err != nil && !isPartial(err) {
+		return err
+	}
+	errs, _ := err.(ConversionErrors)
+	if err = c.applyRules(destPtr, plan, srcValue); err != nil {
+		collected, ok := err.(ConversionErrors)
+		if !ok {
+			return err
+		}
+		errs = append(errs, collected...)
+	}
+	return errs.err()
+}
+
+func (c *Converter) assignFromStruct(destPtr unsafe.Pointer, plan *structPlan, srcValue reflect.Value) error {
+	if !srcValue.CanAddr() {
+		copied := reflect.New(srcValue.Type())
+		copied.Elem().Set(srcValue)
+		srcValue = copied.Elem()
+	}
+	srcPtr := srcValue.Addr().UnsafePointer()
+	marker := plan.srcInfo.marker
+	if marker != nil && !marker.CanUseHolder(srcPtr) {
+		marker = nil
+	}
+	var errs ConversionErrors
+	for i := range plan.pairs {
+		pair := &plan.pairs[i]
+		if marker != nil && pair.src.markerIndex != -1 && !marker.IsSet(srcPtr, pair.src.markerIndex) {
+			continue
+		}
+		fieldPtr := pair.src.pointer(srcPtr, false)
+		if fieldPtr == nil {
+			continue
+		}
+		src := reflect.NewAt(pair.src.rType, fieldPtr).Elem()
+		dest := reflect.NewAt(pair.dest.rType, pair.dest.pointer(destPtr, true)).Elem()
+		if !pair.direct {
+			assigned, err := setStructField(c, dest, src.Interface(), pair.dest.name, pair.dest.name, c.options)
+			if err != nil {
+				if err = c.failure(&errs, err, pair.dest.name, true); err != nil {
+					return err
+				}
+			}
+			if !assigned {
+				continue
+			}
+		} else {
+			dest.Set(src)
+		}
+		c.markAssigned(plan.destInfo, destPtr, pair.dest)
+	}
+	return errs.err()
+}
+
+func (c *Converter) assignFromMap(destPtr unsafe.Pointer, plan *structPlan, srcValue reflect.Value) error {
+	info := plan.destInfo
+	values, ok := srcValue.Interface().(map[string]interface{})
+	if !ok {
+		values = make(map[string]interface{}, srcValue.Len())
+		iter := srcValue.MapRange()
+		for iter.Next() {
+			values[fmt.Sprintf("%v", iter.Key().Interface())] = iter.Value().Interface()
+		}
+	}
+	var lowered map[string]interface{}
+	var errs ConversionErrors
+	for _, dest := range info.bindable {
+		if !dest.exported && !c.options.AccessUnexported || plan.mapped[dest] {
+			continue
+		}
+		var value interface{}
+		found := false
+		c.matchField(dest, func(name string, exact bool) *structField {
+			if !exact {
+				if lowered == nil {
+					lowered = make(map[string]interface{}, len(values))
+					for key, v := range values {
+						lowered[strings.ToLower(key)] = v
+					}
+				}
+				value, found = lowered[strings.ToLower(name)]
+			} else {
+				value, found = values[name]
+			}
+			if found {
+				return dest
+			}
+			return nil
+		})
+		if !found {
+			continue
+		}
+		fieldValue := reflect.NewAt(dest.rType, dest.pointer(destPtr, true)).Elem()
+		assigned, err := setStructField(c, fieldValue, value, dest.name, dest.name, c.options)
+		if err != nil {
+			if err = c.failure(&errs, err, dest.name, true); err != nil {
+				return err
+			}
+		}
+		if assigned {
+			c.markAssigned(info, destPtr, dest)
+		}
+	}
+	return errs.err()
+}
+
+func (c *Converter) markAssigned(info *structInfo, destPtr unsafe.Pointer, field *structField) {
+	if info.marker == nil || field.markerIndex == -1 {
+		return
+	}
+	info.marker.EnsureHolder(destPtr)
+	_ = info.marker.Set(destPtr, field.markerIndex, true)
+}
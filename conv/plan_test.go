@@ -0,0 +1,63 @@
+package conv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type planAudit struct {
+	CreatedBy string
+}
+
+type planSrc struct {
+	ID    int
+	Label string `json:"title"`
+	Owner string
+	Audit planAudit
+}
+
+type planDest struct {
+	*planAudit
+	Id    int64
+	Title string `json:"TITLE"`
+	Owner *string
+}
+
+func TestConverter_StructPlan(t *testing.T) {
+	converter := NewConverter(DefaultOptions())
+	src := planSrc{ID: 3, Label: "x", Owner: "me", Audit: planAudit{CreatedBy: "a"}}
+
+	var dest planDest
+	assert.NoError(t, converter.Convert(&src, &dest))
+	assert.EqualValues(t, 3, dest.Id)
+	assert.Equal(t, "me", *dest.Owner)
+	assert.Nil(t, dest.planAudit)
+
+	var byTag struct {
+		Title string `json:"label"`
+	}
+	assert.NoError(t, converter.Convert(src, &byTag))
+	assert.Equal(t, "x", byTag.Title)
+
+	// same-layout structs still convert as a whole value, mismatched field types bind field by field
+	type sameLayout planSrc
+	var same sameLayout
+	assert.NoError(t, converter.Convert(&src, &same))
+	assert.Equal(t, sameLayout(src), same)
+
+	// embedded pointers on the destination are allocated for promoted fields
+	var embedded planDest
+	assert.NoError(t, converter.Convert(map[string]interface{}{"createdBy": "b"}, &embedded))
+	assert.Equal(t, "b", embedded.CreatedBy)
+
+	// registering a conversion rebuilds compiled plans
+	converter.RegisterConversion(reflect.TypeOf(""), reflect.TypeOf(""), func(src interface{}, dest interface{}, opts Options) error {
+		*dest.(*string) = "[" + src.(string) + "]"
+		return nil
+	})
+	var owner struct{ Owner string }
+	assert.NoError(t, converter.Convert(src, &owner))
+	assert.Equal(t, "[me]", owner.Owner)
+}
@@ -0,0 +1,212 @@
+package conv
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EpochUnit defines the unit of numeric unix timestamps
+type EpochUnit string
+
+const (
+	// EpochAuto reads seconds, or nanoseconds for values above 1e10, and writes seconds (default)
+	EpochAuto EpochUnit = ""
+	// EpochSeconds uses seconds
+	EpochSeconds EpochUnit = "s"
+	// EpochMillis uses milliseconds
+	EpochMillis EpochUnit = "ms"
+	// EpochMicros uses microseconds
+	EpochMicros EpochUnit = "us"
+	// EpochNanos uses nanoseconds
+	EpochNanos EpochUnit = "ns"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// defaultDateLayouts are tried after DateLayout when Options.DateLayouts is empty
+var defaultDateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+func (c *Converter) dateLayout() string {
+	if c.options.DateLayout == "" {
+		return DefaultDateLayout
+	}
+	return c.options.DateLayout
+}
+
+// parseTime parses s with DateLayout followed by DateLayouts; layouts without a zone use Location.
+// Integer strings are read as unix timestamps only when EpochUnit is set explicitly.
+func (c *Converter) parseTime(s string) (time.Time, error) {
+	location := c.options.Location
+	if location == nil {
+		location = time.UTC
+	}
+	layouts := c.options.DateLayouts
+	if len(layouts) == 0 {
+		layouts = defaultDateLayouts
+	}
+	t, err := time.ParseInLocation(c.dateLayout(), s, location)
+	for i := 0; err != nil && i < len(layouts); i++ {
+		t, err = time.ParseInLocation(layouts[i], s, location)
+	}
+	if err == nil {
+		return t, nil
+	}
+	if c.options.EpochUnit != EpochAuto {
+		if epoch, intErr := strconv.ParseInt(s, 10, 64); intErr == nil {
+			t = c.epochTime(epoch)
+			if c.strict() {
+				if err = timeRangeLoss(t, s); err != nil {
+					return time.Time{}, err
+				}
+			}
+			return t, nil
+		}
+	}
+	return t, fmt.Errorf("cannot parse time string '%s': %w", s, err)
+}
+
+// epochTime converts a unix timestamp in EpochUnit
+func (c *Converter) epochTime(epoch int64) time.Time {
+	var t time.Time
+	switch c.options.EpochUnit {
+	case EpochSeconds:
+		t = time.Unix(epoch, 0)
+	case EpochMillis:
+		t = time.UnixMilli(epoch)
+	case EpochMicros:
+		t = time.UnixMicro(epoch)
+	case EpochNanos:
+		t = time.Unix(0, epoch)
+	default:
+		if epoch > 1e10 { // Assuming nanoseconds if value is very large
+			t = time.Unix(0, epoch)
+		} else {
+			t = time.Unix(epoch, 0)
+		}
+	}
+	return c.inLocation(t)
+}
+
+// floatEpochTime converts a fractional unix timestamp in EpochUnit
+func (c *Converter) floatEpochTime(epoch float64) time.Time {
+	seconds := epoch / float64(c.epochScale())
+	unixTime := int64(seconds)
+	fractional := seconds - float64(unixTime)
+	return c.inLocation(time.Unix(unixTime, int64(fractional*1e9)))
+}
+
+// epochScale returns the number of units per second
+func (c *Converter) epochScale() int64 {
+	switch c.options.EpochUnit {
+	case EpochMillis:
+		return 1e3
+	case EpochMicros:
+		return 1e6
+	case EpochNanos:
+		return 1e9
+	}
+	return 1
+}
+
+// timeEpoch returns t as a unix timestamp in EpochUnit
+func (c *Converter) timeEpoch(t time.Time) int64 {
+	switch c.options.EpochUnit {
+	case EpochMillis:
+		return t.UnixMilli()
+	case EpochMicros:
+		return t.UnixMicro()
+	case EpochNanos:
+		return t.UnixNano()
+	}
+	return t.Unix()
+}
+
+func (c *Converter) inLocation(t time.Time) time.Time {
+	if c.options.Location == nil {
+		return t
+	}
+	return t.In(c.options.Location)
+}
+
+// formatTime formats t with DateLayout in Location
+func (c *Converter) formatTime(t time.Time) string {
+	return c.inLocation(t).Format(c.dateLayout())
+}
+
+// parseDuration parses Go duration strings such as 1h30m, ISO-8601 durations such as PT1H30M,
+// and integer nanoseconds.
+func parseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	if d, err := parseISODuration(s); err == nil {
+		return d, nil
+	}
+	if nanos, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(nanos), nil
+	}
+	return 0, fmt.Errorf("cannot parse duration string '%s'", s)
+}
+
+// parseISODuration parses an ISO-8601 duration with week, day, hour, minute and second designators;
+// years and months have no fixed length and are rejected.
+func parseISODuration(s string) (time.Duration, error) {
+	sign := time.Duration(1)
+	if strings.HasPrefix(s, "-") {
+		sign, s = -1, s[1:]
+	}
+	if len(s) < 2 || s[0] != 'P' || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("invalid ISO-8601 duration '%s'", s)
+	}
+	var ret time.Duration
+	inTime := false
+	for s = s[1:]; s != ""; {
+		if s[0] == 'T' && !inTime {
+			inTime, s = true, s[1:]
+			continue
+		}
+		end := strings.IndexAny(s, "WDHMS")
+		if end <= 0 || s[0] < '0' || s[0] > '9' {
+			return 0, fmt.Errorf("invalid ISO-8601 duration component '%s'", s)
+		}
+		value, err := strconv.ParseFloat(strings.Replace(s[:end], ",", ".", 1), 64)
+		if err != nil {
+			return 0, err
+		}
+		var unit time.Duration
+		switch designator := s[end]; {
+		case !inTime && designator == 'W':
+			unit = 7 * 24 * time.Hour
+		case !inTime && designator == 'D':
+			unit = 24 * time.Hour
+		case inTime && designator == 'H':
+			unit = time.Hour
+		case inTime && designator == 'M':
+			unit = time.Minute
+		case inTime && designator == 'S':
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("unsupported ISO-8601 duration designator %q", designator)
+		}
+		if value*float64(unit) >= math.MaxInt64 {
+			return 0, fmt.Errorf("ISO-8601 duration '%s' overflows", s)
+		}
+		ret += time.Duration(value * float64(unit))
+		s = s[end+1:]
+	}
+	return sign * ret, nil
+}
@@ -0,0 +1,103 @@
+package conv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConverter_TimePolicies(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+	ref := time.Date(2024, 3, 1, 10, 30, 0, 0, warsaw)
+
+	options := DefaultOptions()
+	options.DateLayout = "02/01/2006 15:04"
+	options.DateLayouts = []string{"2006-01-02T15:04"}
+	options.Location = warsaw
+	options.EpochUnit = EpochMillis
+	converter := NewConverter(options)
+
+	testCases := []struct {
+		description string
+		src         interface{}
+	}{
+		{description: "date layout", src: "01/03/2024 10:30"},
+		{description: "layouts list", src: "2024-03-01T10:30"},
+		{description: "epoch millis", src: ref.UnixMilli()},
+		{description: "epoch millis string", src: "1709285400000"},
+		{description: "fractional epoch millis", src: float64(ref.UnixMilli())},
+	}
+	for _, testCase := range testCases {
+		actual, err := To[time.Time](converter, testCase.src)
+		if !assert.NoError(t, err, testCase.description) {
+			continue
+		}
+		assert.True(t, ref.Equal(actual), testCase.description)
+		assert.Equal(t, warsaw, actual.Location(), testCase.description)
+	}
+
+	_, err = To[time.Time](converter, time.RFC3339)
+	assert.Error(t, err)
+
+	text, err := To[string](converter, ref.UTC())
+	assert.NoError(t, err)
+	assert.Equal(t, "01/03/2024 10:30", text)
+	millis, err := To[int64](converter, ref)
+	assert.NoError(t, err)
+	assert.Equal(t, ref.UnixMilli(), millis)
+
+	// legacy heuristic treats values above 1e10 as nanoseconds
+	legacy, err := To[time.Time](NewConverter(DefaultOptions()), ref.UnixMilli())
+	assert.NoError(t, err)
+	assert.False(t, ref.Equal(legacy))
+
+	// integer strings are epochs only with an explicit unit
+	_, err = To[time.Time](NewConverter(DefaultOptions()), "20240101")
+	assert.Error(t, err)
+	options.LossPolicy = LossError
+	_, err = To[time.Time](NewConverter(options), "9999999999999999")
+	assert.ErrorIs(t, err, ErrLossyConversion)
+}
+
+func TestConverter_Duration(t *testing.T) {
+	converter := NewConverter(DefaultOptions())
+	testCases := []struct {
+		description string
+		src         interface{}
+		expected    time.Duration
+		expectErr   bool
+	}{
+		{description: "go duration", src: "1h30m", expected: 90 * time.Minute},
+		{description: "iso duration", src: "PT1H", expected: time.Hour},
+		{description: "iso days and fraction", src: "P1DT0.5S", expected: 24*time.Hour + 500*time.Millisecond},
+		{description: "negative iso weeks", src: "-P2W", expected: -14 * 24 * time.Hour},
+		{description: "nanoseconds", src: int64(1500), expected: 1500},
+		{description: "iso months", src: "P1M", expectErr: true},
+		{description: "iso without components", src: "PT", expectErr: true},
+		{description: "iso empty time part", src: "P1DT", expectErr: true},
+		{description: "iso negative component", src: "P1DT-1H", expectErr: true},
+		{description: "iso signed component", src: "PT+1H", expectErr: true},
+		{description: "invalid", src: "soon", expectErr: true},
+	}
+	for _, testCase := range testCases {
+		actual, err := To[time.Duration](converter, testCase.src)
+		if testCase.expectErr {
+			assert.Error(t, err, testCase.description)
+			continue
+		}
+		assert.NoError(t, err, testCase.description)
+		assert.Equal(t, testCase.expected, actual, testCase.description)
+	}
+
+	text, err := To[string](converter, 90*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "1h30m0s", text)
+
+	var record struct{ Timeout time.Duration }
+	assert.NoError(t, converter.Convert(map[string]interface{}{"Timeout": "PT30S"}, &record))
+	assert.Equal(t, 30*time.Second, record.Timeout)
+}
@@ -0,0 +1,20 @@
+package conv
+
+import "reflect"
+
+// To converts src into a new value of type T; pointer types are allocated before conversion.
+func To[T any](c *Converter, src interface{}) (T, error) {
+	var ret T
+	if rType := reflect.TypeOf(ret); rType != nil && rType.Kind() == reflect.Ptr {
+		ret = reflect.New(rType.Elem()).Interface().(T)
+	}
+	err := c.Convert(src, &ret)
+	return ret, err
+}
+
+// SliceTo converts a slice, array or single value src into a slice of T
+func SliceTo[T any](c *Converter, src interface{}) ([]T, error) {
+	var ret []T
+	err := c.Convert(src, &ret)
+	return ret, err
+}
@@ -0,0 +1,83 @@
+package conv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type typedEvent struct {
+	Name string
+	At   time.Time
+}
+
+type typedEventRecord struct {
+	ID   int
+	Name string
+}
+
+func TestTo(t *testing.T) {
+	converter := NewConverter(DefaultOptions())
+
+	count, err := To[int](converter, "42")
+	assert.NoError(t, err)
+	assert.Equal(t, 42, count)
+
+	event, err := To[*typedEvent](converter, map[string]interface{}{"Name": "deploy"})
+	assert.NoError(t, err)
+	assert.Equal(t, &typedEvent{Name: "deploy"}, event)
+
+	_, err = To[int](converter, "x")
+	assert.Error(t, err)
+
+	names, err := SliceTo[string](converter, []interface{}{"a", 2, true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "2", "true"}, names)
+
+	events, err := SliceTo[typedEvent](converter, []map[string]interface{}{{"Name": "a"}, {"Name": "b"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []typedEvent{{Name: "a"}, {Name: "b"}}, events)
+}
+
+func TestConverter_NewChild(t *testing.T) {
+	parent := NewConverter(DefaultOptions())
+	upper := func(src interface{}, dest interface{}, opts Options) error {
+		*dest.(*string) = strings.ToUpper(src.(string))
+		return nil
+	}
+
+	options := parent.Options()
+	options.DateLayout = "2006/01/02"
+	child := parent.NewChild(options)
+
+	event, err := To[typedEvent](child, map[string]interface{}{"Name": "a", "At": "2024/05/06"})
+	assert.NoError(t, err)
+	assert.Equal(t, typedEvent{Name: "a", At: time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)}, event)
+	_, err = To[time.Time](parent, "2024/05/06")
+	assert.Error(t, err)
+
+	event, err = To[typedEvent](child, typedEventRecord{Name: "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, "b", event.Name)
+
+	// conversions registered on the parent later are inherited, including by cached plans
+	parent.RegisterConversion(reflect.TypeOf(""), reflect.TypeOf(""), upper)
+	event, err = To[typedEvent](child, typedEventRecord{Name: "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, "B", event.Name)
+
+	// child registrations override the parent without affecting it
+	child.RegisterConversion(reflect.TypeOf(""), reflect.TypeOf(""), func(src interface{}, dest interface{}, opts Options) error {
+		*dest.(*string) = src.(string) + "!"
+		return nil
+	})
+	event, err = To[typedEvent](child, typedEventRecord{Name: "c"})
+	assert.NoError(t, err)
+	assert.Equal(t, "c!", event.Name)
+	event, err = To[typedEvent](parent, typedEventRecord{Name: "c"})
+	assert.NoError(t, err)
+	assert.Equal(t, "C", event.Name)
+}
@@ -0,0 +1,381 @@
+package conv
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// valuesTags are struct tags naming url.Values keys, in order of precedence
+var valuesTags = []string{"form", "query"}
+
+var valuesConverter = NewConverter(DefaultOptions())
+
+// FromValues binds values to dest, a pointer to a struct, with the default converter; see Converter.FromValues.
+func FromValues(values url.Values, dest interface{}) error {
+	return valuesConverter.FromValues(values, dest)
+}
+
+// ToValues encodes src with the default converter; see Converter.ToValues.
+func ToValues(src interface{}) (url.Values, error) {
+	return valuesConverter.ToValues(src)
+}
+
+type valuesNode struct {
+	values   []string
+	children map[string]*valuesNode
+	items    map[int]*valuesNode
+}
+
+func (n *valuesNode) child(name string) *valuesNode {
+	if n.children == nil {
+		n.children = map[string]*valuesNode{}
+	}
+	ret, ok := n.children[name]
+	if !ok {
+		ret = &valuesNode{}
+		n.children[name] = ret
+	}
+	return ret
+}
+
+func (n *valuesNode) item(index int) *valuesNode {
+	if n.items == nil {
+		n.items = map[int]*valuesNode{}
+	}
+	ret, ok := n.items[index]
+	if !ok {
+		ret = &valuesNode{}
+		n.items[index] = ret
+	}
+	return ret
+}
+
+// FromValues binds values to dest, a pointer to a struct. Keys are matched against `form`, `query` and
+// TagName tags, then field names; dotted and bracketed keys (filter.status, items[0].id, tags[]) address
+// nested structs, slice items and map entries. Slices take repeated keys; a slice field tagged with the
+// split option (`form:"ids,split"`) also takes comma separated, optionally bracketed, lists. Non-slice
+// fields take a single value and report a ConversionError when the key is repeated.
+// Every bound field is flagged in its struct setMarker holder.
+func (c *Converter) FromValues(values url.Values, dest interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() || destValue.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot bind values to %T: expected pointer to struct", dest)
+	}
+	root := &valuesNode{}
+	for key, items := range values {
+		node := root
+		for _, segment := range splitValuesKey(key) {
+			if index, err := strconv.Atoi(segment); err == nil && segment != "" && node != root {
+				node = node.item(index)
+				continue
+			}
+			node = node.child(segment)
+		}
+		node.values = append(node.values, items...)
+	}
+	return c.bindStruct(destValue.Elem(), root)
+}
+
+// splitValuesKey splits items[0].id into items, 0, id; empty brackets are dropped.
+func splitValuesKey(key string) []string {
+	var ret []string
+	for _, part := range strings.Split(key, ".") {
+		for part != "" {
+			open := strings.IndexByte(part, '[')
+			if open == -1 {
+				ret = append(ret, part)
+				break
+			}
+			if open > 0 {
+				ret = append(ret, part[:open])
+			}
+			end := strings.IndexByte(part[open:], ']')
+			if end == -1 {
+				ret = append(ret, part[open:])
+				break
+			}
+			if segment := part[open+1 : open+end]; segment != "" {
+				ret = append(ret, segment)
+			}
+			part = part[open+end+1:]
+		}
+	}
+	return ret
+}
+
+func (c *Converter) bindStruct(value reflect.Value, node *valuesNode) error {
+	ptr := unsafe.Pointer(value.Addr().Pointer())
+	info := c.getStructInfo(value.Type())
+	var errs ConversionErrors
+	for _, field := range info.bindable {
+		if field.valuesKey == "-" || field.tagName == "-" || !field.exported && !c.options.AccessUnexported {
+			continue
+		}
+		child := c.valuesChild(node, field)
+		if child == nil {
+			continue
+		}
+		fieldValue := reflect.NewAt(field.rType, field.pointer(ptr, true)).Elem()
+		if err := c.bindValue(fieldValue, child, field.valuesSplit); err != nil {
+			partial := isPartial(err)
+			if err = c.failure(&errs, err, c.valuesName(field), false); err != nil {
+				return err
+			}
+			if !partial {
+				continue
+			}
+		}
+		c.markAssigned(info, ptr, field)
+	}
+	return errs.err()
+}
+
+// valuesChild finds the node of field by its form/query tag, TagName tag or name
+func (c *Converter) valuesChild(node *valuesNode, field *structField) *valuesNode {
+	candidates := []string{field.valuesKey, field.key, field.name}
+	for _, candidate := range candidates {
+		if child, ok := node.children[candidate]; ok && candidate != "" {
+			return child
+		}
+	}
+	if c.options.CaseSensitive {
+		return nil
+	}
+	for name, child := range node.children {
+		for _, candidate := range candidates {
+			if candidate != "" && strings.EqualFold(name, candidate) {
+				return child
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Converter) valuesName(field *structField) string {
+	if field.valuesKey != "" {
+		return field.valuesKey
+	}
+	return field.key
+}
+
+func (c *Converter) bindValue(value reflect.Value, node *valuesNode, split bool) error {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		return c.bindValue(value.Elem(), node, split)
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		if len(node.children) > 0 && !isLeafStruct(value.Type()) {
+			return c.bindStruct(value, node)
+		}
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		if len(node.items) > 0 {
+			return c.bindItems(value, node)
+		}
+		items := node.values
+		if split {
+			items = nil
+			for _, text := range node.values {
+				items = append(items, splitRepeated(text)...)
+			}
+		}
+		return c.Convert(items, value.Addr().Interface())
+	case reflect.Map:
+		return c.bindMap(value, node)
+	}
+	switch len(node.values) {
+	case 0:
+		return nil
+	case 1:
+		return c.Convert(node.values[0], value.Addr().Interface())
+	}
+	return &ConversionError{Err: fmt.Errorf("expected a single value for %s, got %d", value.Type(), len(node.values))}
+}
+
+// bindItems binds indexed items; an index may extend the slice by at most the number of bound items,
+// so a large index in the query cannot force a large allocation.
+func (c *Converter) bindItems(value reflect.Value, node *valuesNode) error {
+	limit := value.Len() + len(node.items)
+	indexes := make([]int, 0, len(node.items))
+	var errs ConversionErrors
+	for index := range node.items {
+		if index < 0 || index >= limit {
+			err := &ConversionError{Path: fmt.Sprintf("[%d]", index), Err: fmt.Errorf("index out of range [0,%d)", limit)}
+			if err := c.failure(&errs, err, "", false); err != nil {
+				return err
+			}
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+	if len(indexes) == 0 {
+		return errs.err()
+	}
+	sort.Ints(indexes)
+	if last := indexes[len(indexes)-1]; last >= value.Len() {
+		grown := reflect.MakeSlice(value.Type(), last+1, last+1)
+		reflect.Copy(grown, value)
+		value.Set(grown)
+	}
+	for _, index := range indexes {
+		if err := c.bindValue(value.Index(index), node.items[index], false); err != nil {
+			if err = c.failure(&errs, err, fmt.Sprintf("[%d]", index), false); err != nil {
+				return err
+			}
+		}
+	}
+	return errs.err()
+}
+
+func (c *Converter) bindMap(value reflect.Value, node *valuesNode) error {
+	if value.IsNil() {
+		value.Set(reflect.MakeMap(value.Type()))
+	}
+	var errs ConversionErrors
+	for name, child := range node.children {
+		key := reflect.New(value.Type().Key())
+		entry := reflect.New(value.Type().Elem())
+		err := c.Convert(name, key.Interface())
+		if err == nil {
+			err = c.bindValue(entry.Elem(), child, false)
+		}
+		if err != nil {
+			if err = c.failure(&errs, err, name, false); err != nil {
+				return err
+			}
+			continue
+		}
+		value.SetMapIndex(key.Elem(), entry.Elem())
+	}
+	return errs.err()
+}
+
+// splitRepeated splits a comma separated, optionally bracketed list, skipping blank items.
+func splitRepeated(text string) []string {
+	if len(text) > 1 && text[0] == '[' && text[len(text)-1] == ']' {
+		text = text[1 : len(text)-1]
+	}
+	var ret []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+// ToValues encodes src, a struct or a pointer to one, as url.Values keyed like FromValues reads them:
+// nested structs and maps use dotted keys, slices of structs indexed keys and other slices repeated keys,
+// so items containing commas round-trip unless the field uses the split option.
+// Nil pointers and zero omitempty fields are left out, and so are fields not flagged by a setMarker
+// holder when OnlySetFields is enabled.
+func (c *Converter) ToValues(src interface{}) (url.Values, error) {
+	value := indirect(reflect.ValueOf(src))
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot convert %T to values: expected struct", src)
+	}
+	ret := url.Values{}
+	return ret, c.encodeStruct(ret, "", value)
+}
+
+func (c *Converter) encodeStruct(values url.Values, prefix string, value reflect.Value) error {
+	if !value.CanAddr() {
+		copied := reflect.New(value.Type())
+		copied.Elem().Set(value)
+		value = copied.Elem()
+	}
+	ptr := value.Addr().UnsafePointer()
+	info := c.getStructInfo(value.Type())
+	marker := info.marker
+	if !c.options.OnlySetFields || marker != nil && !marker.CanUseHolder(ptr) {
+		marker = nil
+	}
+	for _, field := range info.bindable {
+		if field.valuesKey == "-" || field.tagName == "-" || !field.exported && !c.options.AccessUnexported {
+			continue
+		}
+		if marker != nil && field.markerIndex != -1 && !marker.IsSet(ptr, field.markerIndex) {
+			continue
+		}
+		fieldPtr := field.pointer(ptr, false)
+		if fieldPtr == nil {
+			continue
+		}
+		fieldValue := reflect.NewAt(field.rType, fieldPtr).Elem()
+		if (field.omitEmpty || field.valuesOmitEmpty) && fieldValue.IsZero() {
+			continue
+		}
+		if err := c.encodeValue(values, prefix+c.valuesName(field), fieldValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Converter) encodeValue(values url.Values, key string, value reflect.Value) error {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		if !isLeafStruct(value.Type()) {
+			return c.encodeStruct(values, key+".", value)
+		}
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		nested := nestsStruct(value.Type().Elem())
+		for i := 0; i < value.Len(); i++ {
+			itemKey := key
+			if nested {
+				itemKey = fmt.Sprintf("%s[%d]", key, i)
+			}
+			if err := c.encodeValue(values, itemKey, value.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			if err := c.encodeValue(values, fmt.Sprintf("%s.%v", key, iter.Key().Interface()), iter.Value()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var text string
+	if err := c.Convert(value.Interface(), &text); err != nil {
+		return withPath(err, key)
+	}
+	values.Add(key, text)
+	return nil
+}
+
+// valuesTagName returns the key named by the first form or query tag and whether it has the omitempty
+// and split options
+func valuesTagName(tag reflect.StructTag) (string, bool, bool) {
+	for _, name := range valuesTags {
+		if value, ok := tag.Lookup(name); ok {
+			if name, options, _ := strings.Cut(value, ","); name != "" {
+				options = "," + options + ","
+				return name, strings.Contains(options, ",omitempty,"), strings.Contains(options, ",split,")
+			}
+		}
+	}
+	return "", false, false
+}
@@ -0,0 +1,167 @@
+package conv
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type valuesRange struct {
+	From int `form:"from"`
+	To   int `form:"to"`
+}
+
+type valuesFilterHas struct {
+	Status bool
+	Age    bool
+}
+
+type valuesFilter struct {
+	Status string           `form:"status"`
+	Age    *valuesRange     `form:"age"`
+	Has    *valuesFilterHas `setMarker:"true"`
+}
+
+type valuesItem struct {
+	ID   int    `query:"id"`
+	Name string `query:"name"`
+}
+
+type valuesQueryHas struct {
+	Query  bool
+	Limit  bool
+	IDs    bool
+	Filter bool
+	Items  bool
+	Since  bool
+	Labels bool
+	Secret bool
+}
+
+type valuesQuery struct {
+	Query  string            `form:"q"`
+	Limit  int               `json:"limit"`
+	IDs    []int             `form:"id,split"`
+	Filter valuesFilter      `form:"filter"`
+	Items  []*valuesItem     `form:"items"`
+	Since  time.Duration     `form:"since,omitempty"`
+	Labels map[string]string `form:"labels"`
+	Secret string            `form:"-"`
+	Has    *valuesQueryHas   `setMarker:"true"`
+}
+
+func TestFromValues(t *testing.T) {
+	values := url.Values{
+		"q":                 {"shoes"},
+		"LIMIT":             {"20"},
+		"id":                {"1", "2,3", "[4]"},
+		"filter.status":     {"active"},
+		"filter[age][from]": {"18"},
+		"items[1].id":       {"7"},
+		"items[0].name":     {"a"},
+		"since":             {"PT1H"},
+		"labels.color":      {"red"},
+		"Secret":            {"x"},
+		"unknown":           {"ignored"},
+	}
+	var query valuesQuery
+	assert.NoError(t, FromValues(values, &query))
+	assert.Equal(t, "shoes", query.Query)
+	assert.Equal(t, 20, query.Limit)
+	assert.Equal(t, []int{1, 2, 3, 4}, query.IDs)
+	assert.Equal(t, "active", query.Filter.Status)
+	assert.Equal(t, &valuesRange{From: 18}, query.Filter.Age)
+	assert.Equal(t, []*valuesItem{{Name: "a"}, {ID: 7}}, query.Items)
+	assert.Equal(t, time.Hour, query.Since)
+	assert.Equal(t, map[string]string{"color": "red"}, query.Labels)
+	assert.Empty(t, query.Secret)
+	assert.Equal(t, &valuesQueryHas{Query: true, Limit: true, IDs: true, Filter: true, Items: true, Since: true, Labels: true}, query.Has)
+	assert.Equal(t, &valuesFilterHas{Status: true, Age: true}, query.Filter.Has)
+
+	err := FromValues(url.Values{"filter.age.to": {"old"}}, &query)
+	var conversionErr *ConversionError
+	if assert.True(t, errors.As(err, &conversionErr)) {
+		assert.Equal(t, "filter.age.to", conversionErr.Path)
+	}
+	assert.Error(t, FromValues(values, query))
+
+	query = valuesQuery{}
+	err = FromValues(url.Values{"items[100000000].id": {"1"}}, &query)
+	if assert.True(t, errors.As(err, &conversionErr)) {
+		assert.Equal(t, "items[100000000]", conversionErr.Path)
+	}
+	assert.Empty(t, query.Items)
+
+	options := DefaultOptions()
+	options.CollectErrors = true
+	query = valuesQuery{}
+	err = NewConverter(options).FromValues(url.Values{"items[-1].id": {"1"}, "items[0].id": {"2"}, "items[5].id": {"3"}}, &query)
+	var conversionErrs ConversionErrors
+	if assert.True(t, errors.As(err, &conversionErrs)) {
+		assert.Len(t, conversionErrs, 2)
+	}
+	assert.Equal(t, []*valuesItem{{ID: 2}}, query.Items)
+}
+
+func TestToValues(t *testing.T) {
+	query := valuesQuery{
+		Query:  "shoes",
+		Limit:  20,
+		IDs:    []int{1, 2},
+		Filter: valuesFilter{Status: "active", Age: &valuesRange{From: 18, To: 30}},
+		Items:  []*valuesItem{{ID: 7, Name: "a"}},
+		Labels: map[string]string{"color": "red"},
+		Secret: "x",
+	}
+	values, err := ToValues(&query)
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{
+		"q":               {"shoes"},
+		"limit":           {"20"},
+		"id":              {"1", "2"},
+		"filter.status":   {"active"},
+		"filter.age.from": {"18"},
+		"filter.age.to":   {"30"},
+		"items[0].id":     {"7"},
+		"items[0].name":   {"a"},
+		"labels.color":    {"red"},
+	}, values)
+
+	var restored valuesQuery
+	assert.NoError(t, FromValues(values, &restored))
+	restored.Has, restored.Filter.Has, query.Secret = nil, nil, ""
+	assert.Equal(t, query, restored)
+
+	options := DefaultOptions()
+	options.OnlySetFields = true
+	values, err = NewConverter(options).ToValues(valuesQuery{Query: "q", Limit: 5, Has: &valuesQueryHas{Limit: true}})
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"limit": {"5"}}, values)
+}
+
+func TestValues_RepeatedKeys(t *testing.T) {
+	type search struct {
+		Tags  []string `form:"tags"`
+		Names []string `form:"names,split"`
+		Limit int      `form:"limit"`
+	}
+	src := search{Tags: []string{"a,b", "c"}}
+	values, err := ToValues(src)
+	assert.NoError(t, err)
+	var restored search
+	assert.NoError(t, FromValues(values, &restored))
+	assert.Equal(t, src, restored)
+
+	restored = search{}
+	assert.NoError(t, FromValues(url.Values{"names": {"a,b", "[c]"}}, &restored))
+	assert.Equal(t, []string{"a", "b", "c"}, restored.Names)
+
+	err = FromValues(url.Values{"limit": {"1", "2"}}, &restored)
+	var conversionErr *ConversionError
+	if assert.True(t, errors.As(err, &conversionErr)) {
+		assert.Equal(t, "limit", conversionErr.Path)
+	}
+}
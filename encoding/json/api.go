@@ -117,6 +117,9 @@ func UnmarshalContext(ctx context.Context, data []byte, dest interface{}, opts .
 		compileName = func(field string) string { return tr.Transform("", field) }
 	}
 	u := jsonunmarshal.New(cfg.Ctx, cfg.scannerHooks, unknown, number, nulls, duplicates, malformed, cfg.TimeLayout, caseKey, compileName, cfg.PathUnmarshalHook)
+	u.Projection = jsonunmarshal.NewProjection(cfg.Paths...)
+	u.AliasInput = cfg.AliasInput
+	u.MergeContainers = cfg.MergeContainers
 	return u.Unmarshal(data, dest)
 }
 
@@ -125,6 +128,12 @@ func Unmarshal(data []byte, dest interface{}, opts ...Option) error {
 	return UnmarshalContext(context.Background(), data, dest, opts...)
 }
 
+// UnmarshalPaths decodes only the supplied dotted paths into a struct destination.
+// Markers are set only for the decoded fields; non-struct destinations are decoded in full.
+func UnmarshalPaths(data []byte, dest interface{}, paths ...string) error {
+	return UnmarshalContext(context.Background(), data, dest, WithPaths(paths...))
+}
+
 func isPointerToStruct(v interface{}) bool {
 	if v == nil {
 		return false
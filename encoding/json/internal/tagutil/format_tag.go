@@ -100,7 +100,7 @@ func ResolveFieldTag(sf reflect.StructField) ResolvedFieldTag {
 		Explicit:  explicit,
 		OmitEmpty: jTag.OmitEmpty || fTag.OmitEmpty,
 		Ignore:    jTag.Transient || sf.Tag.Get("internal") == "true" || fTag.Ignore,
-		Inline:    sf.Anonymous || sf.Tag.Get("jsonx") == "inline" || fTag.Inline,
+		Inline:    sf.Anonymous || HasJSONXOption(sf.Tag, "inline") || fTag.Inline,
 		Format:    fTag,
 	}
 }
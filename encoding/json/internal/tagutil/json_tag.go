@@ -1,6 +1,9 @@
 package tagutil
 
-import "strings"
+import (
+	"reflect"
+	"strings"
+)
 
 type JSONTag struct {
 	Name      string
@@ -32,3 +35,20 @@ func ParseJSONTag(defaultName string, raw string) JSONTag {
 	}
 	return tag
 }
+
+// HasJSONXOption reports whether the comma-separated `jsonx` tag contains option (for example "inline" or "key").
+func HasJSONXOption(tag reflect.StructTag, option string) bool {
+	raw := tag.Get("jsonx")
+	for raw != "" {
+		part := raw
+		if i := strings.IndexByte(raw, ','); i != -1 {
+			part, raw = raw[:i], raw[i+1:]
+		} else {
+			raw = ""
+		}
+		if strings.TrimSpace(part) == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package union
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/viant/structology/encoding/json/internal/tagutil"
+)
+
+// Union describes an interface type decoded into concrete types selected by a discriminator field.
+type Union struct {
+	Interface     reflect.Type
+	Discriminator string
+	Types         map[string]reflect.Type
+	labels        map[reflect.Type]string
+	hasField      map[reflect.Type]bool
+}
+
+var registry sync.Map // reflect.Type -> *Union
+
+// New validates and compiles a union; types may be struct types or pointers to structs.
+func New(iface reflect.Type, discriminator string, types map[string]reflect.Type) (*Union, error) {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		return nil, fmt.Errorf("union type must be an interface, got %v", iface)
+	}
+	if discriminator == "" {
+		return nil, fmt.Errorf("union %s: discriminator was empty", iface)
+	}
+	ret := &Union{Interface: iface, Discriminator: discriminator, Types: map[string]reflect.Type{}, labels: map[reflect.Type]string{}, hasField: map[reflect.Type]bool{}}
+	for label, rType := range types {
+		structType := rType
+		if structType.Kind() == reflect.Ptr {
+			structType = structType.Elem()
+		}
+		if structType.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("union %s: %s is not a struct", iface, rType)
+		}
+		if !rType.Implements(iface) {
+			return nil, fmt.Errorf("union %s: %s does not implement it", iface, rType)
+		}
+		ret.Types[label] = rType
+		ret.labels[rType] = label
+		ret.hasField[rType] = hasJSONField(structType, discriminator)
+	}
+	return ret, nil
+}
+
+// Register makes the union visible to the marshal and unmarshal engines.
+func Register(u *Union) {
+	registry.Store(u.Interface, u)
+}
+
+// Lookup returns the union registered for an interface type.
+func Lookup(iface reflect.Type) (*Union, bool) {
+	v, ok := registry.Load(iface)
+	if !ok {
+		return nil, false
+	}
+	return v.(*Union), true
+}
+
+// Label returns the discriminator value of a concrete type.
+func (u *Union) Label(rType reflect.Type) (string, bool) {
+	label, ok := u.labels[rType]
+	return label, ok
+}
+
+// HasField reports whether a concrete type declares the discriminator as a regular JSON field.
+func (u *Union) HasField(rType reflect.Type) bool {
+	return u.hasField[rType]
+}
+
+func hasJSONField(rType reflect.Type, name string) bool {
+	for i := 0; i < rType.NumField(); i++ {
+		sf := rType.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		resolved := tagutil.ResolveFieldTag(sf)
+		if resolved.Ignore {
+			continue
+		}
+		if resolved.Inline {
+			inner := sf.Type
+			if inner.Kind() == reflect.Ptr {
+				inner = inner.Elem()
+			}
+			if inner.Kind() == reflect.Struct && hasJSONField(inner, name) {
+				return true
+			}
+			continue
+		}
+		if strings.EqualFold(resolved.Name, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/francoispqt/gojay"
 	"github.com/viant/structology/encoding/json/internal/tagutil"
+	"github.com/viant/structology/encoding/json/internal/union"
 	"github.com/viant/xunsafe"
 )
 
@@ -351,6 +352,11 @@ func (e *Engine) appendValue(sess *encoderSession, rv reflect.Value) error {
 			return err
 		}
 	}
+	if rv.Kind() == reflect.Interface && !rv.IsNil() && rv.Type().NumMethod() > 0 {
+		if u, ok := union.Lookup(rv.Type()); ok {
+			return e.appendUnion(sess, u, rv.Elem())
+		}
+	}
 	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Ptr {
 		if rv.IsNil() {
 			sess.buf = append(sess.buf, "null"...)
@@ -425,6 +431,27 @@ func (e *Engine) appendValue(sess *encoderSession, rv reflect.Value) error {
 	}
 }
 
+// appendUnion writes a union member and injects its discriminator unless the type declares it as a field.
+func (e *Engine) appendUnion(sess *encoderSession, u *union.Union, rv reflect.Value) error {
+	label, ok := u.Label(rv.Type())
+	start := len(sess.buf)
+	if err := e.appendValue(sess, rv); err != nil {
+		return err
+	}
+	if !ok || u.HasField(rv.Type()) || len(sess.buf) == start || sess.buf[start] != '{' {
+		return nil
+	}
+	entry := appendQuotedName(nil, u.Discriminator)
+	entry = strconv.AppendQuote(entry, label)
+	if len(sess.buf) > start+2 {
+		entry = append(entry, ',')
+	}
+	sess.buf = append(sess.buf, entry...)
+	copy(sess.buf[start+1+len(entry):], sess.buf[start+1:len(sess.buf)-len(entry)])
+	copy(sess.buf[start+1:], entry)
+	return nil
+}
+
 func (e *Engine) tryAppendCustomMarshaler(sess *encoderSession, rv reflect.Value) (bool, error) {
 	if !rv.IsValid() {
 		return false, nil
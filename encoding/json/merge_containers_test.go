@@ -0,0 +1,88 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mergeLineHas struct {
+	Id  bool
+	Qty bool
+	Sku bool
+}
+
+type mergeLine struct {
+	Id  int `jsonx:"key"`
+	Qty int
+	Sku string
+	Has *mergeLineHas `setMarker:"true"`
+}
+
+type mergeOrder struct {
+	Labels map[string]string
+	Attrs  map[string]int
+	Lines  []mergeLine
+	Refs   []*mergeLine
+	Tags   []string
+}
+
+func TestMergeContainers_MapsMergeKeys(t *testing.T) {
+	order := mergeOrder{Labels: map[string]string{"a": "1", "b": "2"}, Attrs: map[string]int{"x": 1}}
+	require.NoError(t, Unmarshal([]byte(`{"Labels":{"b":"3","c":"4"},"Attrs":{"y":2}}`), &order, WithMergeContainers(true)))
+	require.Equal(t, map[string]string{"a": "1", "b": "3", "c": "4"}, order.Labels)
+	require.Equal(t, map[string]int{"x": 1, "y": 2}, order.Attrs)
+
+	require.NoError(t, Unmarshal([]byte(`{"Labels":{"z":"9"}}`), &order))
+	require.Equal(t, map[string]string{"z": "9"}, order.Labels)
+}
+
+func TestMergeContainers_SlicePatchByKey(t *testing.T) {
+	order := mergeOrder{
+		Lines: []mergeLine{{Id: 1, Qty: 1, Sku: "a"}, {Id: 2, Qty: 2, Sku: "b"}},
+		Refs:  []*mergeLine{{Id: 5, Qty: 5, Sku: "e"}},
+		Tags:  []string{"old"},
+	}
+	ref := order.Refs[0]
+	data := []byte(`{"Lines":[{"Id":2,"Qty":20},{"Id":3,"Sku":"c"}],"Refs":[{"Id":5,"Sku":"E"}],"Tags":["new"]}`)
+	require.NoError(t, Unmarshal(data, &order, WithMergeContainers(true)))
+
+	require.Len(t, order.Lines, 3)
+	require.Equal(t, "a", order.Lines[0].Sku)
+	require.Equal(t, 20, order.Lines[1].Qty)
+	require.Equal(t, "b", order.Lines[1].Sku)
+	require.True(t, order.Lines[1].Has.Qty)
+	require.False(t, order.Lines[1].Has.Sku)
+	require.Equal(t, 3, order.Lines[2].Id)
+	require.Equal(t, "c", order.Lines[2].Sku)
+
+	require.Len(t, order.Refs, 1)
+	require.Same(t, ref, order.Refs[0])
+	require.Equal(t, 5, ref.Qty)
+	require.Equal(t, "E", ref.Sku)
+	require.Equal(t, []string{"new"}, order.Tags)
+}
+
+func TestMergeContainers_SlicePatchByIndex(t *testing.T) {
+	type Item struct {
+		Name  string
+		Count int
+	}
+	items := []Item{{Name: "a", Count: 1}, {Name: "b", Count: 2}}
+	require.NoError(t, Unmarshal([]byte(`[{"Count":10},{},{"Name":"c"}]`), &items, WithMergeContainers(true)))
+	require.Equal(t, []Item{{Name: "a", Count: 10}, {Name: "b", Count: 2}, {Name: "c"}}, items)
+}
+
+func TestMergeContainers_NonComparableKey(t *testing.T) {
+	type Line struct {
+		Ids []int `jsonx:"key"`
+		Qty int
+	}
+	type Order struct {
+		Lines []Line
+	}
+	order := Order{Lines: []Line{{Ids: []int{1}, Qty: 1}}}
+	err := Unmarshal([]byte(`{"Lines":[{"Ids":[1],"Qty":2}]}`), &order, WithMergeContainers(true))
+	require.ErrorContains(t, err, "non-comparable")
+	require.Equal(t, 1, order.Lines[0].Qty)
+}
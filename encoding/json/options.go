@@ -106,6 +106,24 @@ func WithNilSlicePolicy(policy NilSlicePolicy) Option {
 	})
 }
 
+// WithPaths restricts unmarshal to the supplied dotted paths (for example "Tenant.Id"); other subtrees are skipped.
+// Paths continue through slice items ("Items.Name") and map keys ("Meta.x").
+func WithPaths(paths ...string) Option {
+	return optionFn(func(o *Options) { o.Paths = append(o.Paths, paths...) })
+}
+
+// WithAliasInput lets Raw values reference the input buffer instead of copying their span.
+// The caller must keep the input unchanged for as long as the decoded Raw values are used.
+func WithAliasInput(enabled bool) Option {
+	return optionFn(func(o *Options) { o.AliasInput = enabled })
+}
+
+// WithMergeContainers merges decoded keys into existing maps and patches existing struct slices by index,
+// or by the element field tagged `jsonx:"key"`, instead of replacing them.
+func WithMergeContainers(enabled bool) Option {
+	return optionFn(func(o *Options) { o.MergeContainers = enabled })
+}
+
 func WithDebugPathSink(sink func(PathRef)) Option {
 	return optionFn(func(o *Options) { o.DebugPathSink = sink })
 }
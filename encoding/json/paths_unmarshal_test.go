@@ -0,0 +1,108 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalPaths_DecodesOnlyRequestedFields(t *testing.T) {
+	type TenantHas struct {
+		Id     bool
+		Region bool
+	}
+	type Tenant struct {
+		Id     string
+		Region string
+		Has    *TenantHas `setMarker:"true"`
+	}
+	type RequestHas struct {
+		Tenant  bool
+		Action  bool
+		Payload bool
+	}
+	type Request struct {
+		Tenant  *Tenant
+		Action  string `json:"action"`
+		Payload []map[string]interface{}
+		Has     *RequestHas `setMarker:"true"`
+	}
+
+	data := []byte(`{"Tenant":{"Id":"t1","Region":"us"},"action":"create","Payload":[{"a":1},{"b":[1,2,{"c":"x"}]}]}`)
+	var out Request
+	require.NoError(t, UnmarshalPaths(data, &out, "Tenant.Id", "Action"))
+	require.NotNil(t, out.Tenant)
+	require.Equal(t, "t1", out.Tenant.Id)
+	require.Equal(t, "", out.Tenant.Region)
+	require.Equal(t, "create", out.Action)
+	require.Nil(t, out.Payload)
+
+	require.True(t, out.Has.Tenant)
+	require.True(t, out.Has.Action)
+	require.False(t, out.Has.Payload)
+	require.True(t, out.Tenant.Has.Id)
+	require.False(t, out.Tenant.Has.Region)
+}
+
+func TestUnmarshalPaths_LeafSelectsWholeSubtree(t *testing.T) {
+	type Tenant struct {
+		Id     string
+		Region string
+	}
+	type Request struct {
+		Tenant Tenant
+		Action string
+	}
+	var out Request
+	require.NoError(t, Unmarshal([]byte(`{"Action":"x","Tenant":{"Id":"t1","Region":"eu"}}`), &out, WithPaths("tenant")))
+	require.Equal(t, Tenant{Id: "t1", Region: "eu"}, out.Tenant)
+	require.Equal(t, "", out.Action)
+}
+
+func TestUnmarshalPaths_SkippedSubtreeMustBeWellFormed(t *testing.T) {
+	type Request struct {
+		Action string
+		Body   map[string]interface{}
+	}
+	var out Request
+	err := UnmarshalPaths([]byte(`{"Body":{"a":[1,2},"Action":"x"}`), &out, "Action")
+	require.Error(t, err)
+}
+
+func TestUnmarshalPaths_SlicesAndMaps(t *testing.T) {
+	type ItemHas struct {
+		Name bool
+		Cost bool
+	}
+	type Item struct {
+		Name string
+		Cost float64
+		Has  *ItemHas `setMarker:"true"`
+	}
+	type Request struct {
+		Items []Item
+		Refs  []*Item
+		Meta  map[string]interface{}
+		ByKey map[string]Item
+	}
+	data := []byte(`{"Items":[{"Name":"a","Cost":1},{"Name":"b","Cost":2,"Extra":{"deep":[1,2]}}],` +
+		`"Refs":[{"Name":"r","Cost":3},null],` +
+		`"Meta":{"x":{"y":1,"z":2},"w":[1,{"a":1}]},` +
+		`"ByKey":{"k":{"Name":"n","Cost":4},"skip":{"Name":"s"}}}`)
+	var out Request
+	require.NoError(t, UnmarshalPaths(data, &out, "Items.Name", "Refs.Cost", "Meta.x.y", "ByKey.k.Name"))
+	require.Len(t, out.Items, 2)
+	require.Equal(t, "b", out.Items[1].Name)
+	require.Zero(t, out.Items[1].Cost)
+	require.True(t, out.Items[0].Has.Name)
+	require.False(t, out.Items[0].Has.Cost)
+	require.Len(t, out.Refs, 2)
+	require.Equal(t, "", out.Refs[0].Name)
+	require.Equal(t, 3.0, out.Refs[0].Cost)
+	require.Nil(t, out.Refs[1])
+	require.Equal(t, map[string]interface{}{"x": map[string]interface{}{"y": int64(1)}}, out.Meta)
+	require.Equal(t, map[string]Item{"k": {Name: "n", Has: &ItemHas{Name: true}}}, out.ByKey)
+
+	// excluded subtrees are skipped, yet must still be well formed
+	require.Error(t, UnmarshalPaths([]byte(`{"Items":[{"Name":"a","Cost":[1,}]}`), &out, "Items.Name"))
+}
@@ -0,0 +1,47 @@
+package json
+
+import (
+	jsonunmarshal "github.com/viant/structology/encoding/json/unmarshal"
+)
+
+// ErrPathNotFound is returned by Get when a path does not address any value.
+var ErrPathNotFound = jsonunmarshal.ErrPathNotFound
+
+// Get returns the raw JSON span addressed by a JSON Pointer ("/items/3/name") or a dotted
+// path ("items[3].name"). The returned span aliases data; no intermediate values are built.
+// The document is validated strictly up to the addressed value only: scanning stops there,
+// so malformed data after it is not reported.
+func Get(data []byte, path string) ([]byte, error) {
+	spans, err := jsonunmarshal.Extract(data, scalarScannerHooks{}, jsonunmarshal.FailFast, path)
+	if err != nil {
+		return nil, err
+	}
+	if spans[0] == nil {
+		return nil, ErrPathNotFound
+	}
+	return spans[0], nil
+}
+
+// GetMany resolves several paths in a single scan; results are aligned with paths and
+// missing paths yield nil spans. As with Get, only the prefix up to the last resolved value is validated.
+func GetMany(data []byte, paths ...string) ([][]byte, error) {
+	return jsonunmarshal.Extract(data, scalarScannerHooks{}, jsonunmarshal.FailFast, paths...)
+}
+
+// GetValue returns the generic value (string, float64, bool, nil, map or slice) addressed by path.
+func GetValue(data []byte, path string) (interface{}, error) {
+	span, err := Get(data, path)
+	if err != nil {
+		return nil, err
+	}
+	return jsonunmarshal.DecodeValue(span, scalarScannerHooks{})
+}
+
+// GetInto decodes the value addressed by path into dest.
+func GetInto(data []byte, path string, dest interface{}, opts ...Option) error {
+	span, err := Get(data, path)
+	if err != nil {
+		return err
+	}
+	return Unmarshal(span, dest, opts...)
+}
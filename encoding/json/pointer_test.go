@@ -0,0 +1,86 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var pointerDoc = []byte(`{"user":{"id":7,"name":"Ann"},"items":[{"name":"a"},{"name":"b"},{"name":"c"},{"name":"d","tags":["x","y"]}],"a/b":{"m~n":true},"trailer":[1,2,3]}`)
+
+func TestGet_PointerAndDottedPaths(t *testing.T) {
+	testCases := []struct {
+		description string
+		path        string
+		expect      string
+	}{
+		{description: "pointer", path: "/items/3/name", expect: `"d"`},
+		{description: "dotted", path: "items[3].name", expect: `"d"`},
+		{description: "json path", path: "$.items[3].tags[1]", expect: `"y"`},
+		{description: "object span", path: "/user", expect: `{"id":7,"name":"Ann"}`},
+		{description: "escaped", path: "/a~1b/m~0n", expect: `true`},
+		{description: "root", path: "", expect: string(pointerDoc)},
+	}
+	for _, testCase := range testCases {
+		span, err := Get(pointerDoc, testCase.path)
+		require.NoError(t, err, testCase.description)
+		require.Equal(t, testCase.expect, string(span), testCase.description)
+	}
+}
+
+func TestGet_NotFound(t *testing.T) {
+	for _, path := range []string{"/items/9/name", "/user/missing", "/user/id/x"} {
+		_, err := Get(pointerDoc, path)
+		require.ErrorIs(t, err, ErrPathNotFound, path)
+	}
+}
+
+func TestGetMany_SinglePass(t *testing.T) {
+	spans, err := GetMany(pointerDoc, "/user/id", "/items/0/name", "/missing", "/items/0")
+	require.NoError(t, err)
+	require.Equal(t, "7", string(spans[0]))
+	require.Equal(t, `"a"`, string(spans[1]))
+	require.Nil(t, spans[2])
+	require.Equal(t, `{"name":"a"}`, string(spans[3]))
+
+	// scanning stops once every path is resolved, so a malformed tail is never reached
+	spans, err = GetMany([]byte(`{"id":1,"rest":[1,2`), "id")
+	require.NoError(t, err)
+	require.Equal(t, "1", string(spans[0]))
+}
+
+func TestGetValueAndInto(t *testing.T) {
+	value, err := GetValue(pointerDoc, "/items/3/tags")
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"x", "y"}, value)
+
+	type User struct {
+		Id   int
+		Name string
+	}
+	var user User
+	require.NoError(t, GetInto(pointerDoc, "user", &user))
+	require.Equal(t, User{Id: 7, Name: "Ann"}, user)
+}
+
+func TestGet_RejectsNonCanonicalIndexesAndMalformed(t *testing.T) {
+	for _, path := range []string{"/items/01/name", "/items/+1/name", "/items/-1/name"} {
+		_, err := Get(pointerDoc, path)
+		require.ErrorIs(t, err, ErrPathNotFound, path)
+	}
+	span, err := Get(pointerDoc, "/items/0/name")
+	require.NoError(t, err)
+	require.Equal(t, `"a"`, string(span))
+
+	_, err = Get([]byte(`{"items":[1,2,],"id":3}`), "/id")
+	require.Error(t, err)
+
+	// only the prefix up to the addressed value is validated
+	span, err = Get([]byte(`{"id":3,"items":[1,2,}`), "/id")
+	require.NoError(t, err)
+	require.Equal(t, "3", string(span))
+	_, err = Get([]byte(`{"id":3,"items":[1,2,}`), "/missing")
+	require.Error(t, err)
+	_, err = GetMany([]byte(`{"a":1,,"id":3}`), "/id")
+	require.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package json
+
+import (
+	"reflect"
+	"unsafe"
+
+	jsonunmarshal "github.com/viant/structology/encoding/json/unmarshal"
+)
+
+// Kind identifies the JSON type of a raw value.
+type Kind int
+
+const (
+	KindInvalid Kind = iota
+	KindNull
+	KindBool
+	KindNumber
+	KindString
+	KindObject
+	KindArray
+)
+
+// String returns the kind name.
+func (k Kind) String() string {
+	switch k {
+	case KindNull:
+		return "null"
+	case KindBool:
+		return "bool"
+	case KindNumber:
+		return "number"
+	case KindString:
+		return "string"
+	case KindObject:
+		return "object"
+	case KindArray:
+		return "array"
+	}
+	return "invalid"
+}
+
+// Raw holds an undecoded JSON value span together with its document path.
+// Use it for polymorphic payloads: decode the envelope first, then Decode the span into the concrete type.
+// Spans are copied unless unmarshal runs WithAliasInput(true).
+type Raw struct {
+	data []byte
+	path string
+}
+
+func init() {
+	jsonunmarshal.RegisterSpanType(reflect.TypeOf(Raw{}), func(ptr unsafe.Pointer, span []byte, path string) {
+		*(*Raw)(ptr) = Raw{data: span, path: path}
+	})
+}
+
+// NewRaw creates a raw value from JSON bytes.
+func NewRaw(data []byte) Raw {
+	return Raw{data: data}
+}
+
+// Bytes returns the raw JSON span.
+func (r Raw) Bytes() []byte { return r.data }
+
+// Path returns the dotted document path the value was captured at (for example "items[2].payload").
+func (r Raw) Path() string { return r.path }
+
+// Kind returns the JSON type of the value.
+func (r Raw) Kind() Kind {
+	for _, c := range r.data {
+		switch c {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case 'n':
+			return KindNull
+		case 't', 'f':
+			return KindBool
+		case '"':
+			return KindString
+		case '{':
+			return KindObject
+		case '[':
+			return KindArray
+		case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			return KindNumber
+		}
+		return KindInvalid
+	}
+	return KindInvalid
+}
+
+// Decode unmarshals the span into dest.
+func (r Raw) Decode(dest interface{}, opts ...Option) error {
+	return Unmarshal(r.data, dest, opts...)
+}
+
+// MarshalJSON emits the span unchanged; an empty Raw marshals as null.
+func (r Raw) MarshalJSON() ([]byte, error) {
+	if len(r.data) == 0 {
+		return []byte("null"), nil
+	}
+	return r.data, nil
+}
+
+// UnmarshalJSON copies data, keeping Raw usable with encoding/json.
+func (r *Raw) UnmarshalJSON(data []byte) error {
+	r.data = append(r.data[:0], data...)
+	r.path = ""
+	return nil
+}
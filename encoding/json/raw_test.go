@@ -0,0 +1,108 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type rawEnvelope struct {
+	Type    string
+	Payload Raw
+}
+
+type rawCreated struct {
+	Id   int
+	Name string
+}
+
+func TestRaw_TwoPhaseDecode(t *testing.T) {
+	data := []byte(`{"Type":"created","Payload":{"Id":3,"Name":"x"}}`)
+	var envelope rawEnvelope
+	require.NoError(t, Unmarshal(data, &envelope))
+	require.Equal(t, "created", envelope.Type)
+	require.Equal(t, KindObject, envelope.Payload.Kind())
+	require.Equal(t, "Payload", envelope.Payload.Path())
+	require.Equal(t, `{"Id":3,"Name":"x"}`, string(envelope.Payload.Bytes()))
+
+	var created rawCreated
+	require.NoError(t, envelope.Payload.Decode(&created))
+	require.Equal(t, rawCreated{Id: 3, Name: "x"}, created)
+
+	out, err := Marshal(envelope)
+	require.NoError(t, err)
+	require.JSONEq(t, string(data), string(out))
+}
+
+func TestRaw_AliasInput(t *testing.T) {
+	data := []byte(`{"Type":"a","Payload":[1,2]}`)
+	var copied, aliased rawEnvelope
+	require.NoError(t, Unmarshal(data, &copied))
+	require.NoError(t, Unmarshal(data, &aliased, WithAliasInput(true)))
+	data[23] = '9'
+	require.Equal(t, "[1,2]", string(copied.Payload.Bytes()))
+	require.Equal(t, "[9,2]", string(aliased.Payload.Bytes()))
+}
+
+func TestRaw_ContainersAndPaths(t *testing.T) {
+	type Item struct {
+		Kind string
+		Body *Raw
+	}
+	type Doc struct {
+		Items  []Item
+		Parts  []Raw
+		ByName map[string]Raw
+	}
+	data := []byte(`{"Items":[{"Kind":"a","Body":"s"},{"Kind":"b","Body":null},{"Kind":"c","Body":true}],"Parts":[1,{"x":null}],"ByName":{"k":-2.5}}`)
+	var doc Doc
+	require.NoError(t, Unmarshal(data, &doc))
+	require.Len(t, doc.Items, 3)
+	require.Equal(t, "Items[0].Body", doc.Items[0].Body.Path())
+	require.Equal(t, KindString, doc.Items[0].Body.Kind())
+	require.Nil(t, doc.Items[1].Body)
+	require.Equal(t, KindBool, doc.Items[2].Body.Kind())
+	require.Equal(t, "Parts[1]", doc.Parts[1].Path())
+	require.Equal(t, KindObject, doc.Parts[1].Kind())
+	require.Equal(t, "ByName.k", doc.ByName["k"].Path())
+	require.Equal(t, KindNumber, doc.ByName["k"].Kind())
+
+	var root Raw
+	require.NoError(t, Unmarshal([]byte(` null `), &root))
+	require.Equal(t, KindNull, root.Kind())
+}
+
+func TestRaw_EmptyKeyNullItemsAndPolicies(t *testing.T) {
+	type Item struct {
+		Kind string
+		Body Raw
+	}
+	type Doc struct {
+		Items  []*Item
+		ByName map[string]Raw
+	}
+	var doc Doc
+	require.NoError(t, Unmarshal([]byte(`{"ByName":{"":1}}`), &doc))
+	require.Equal(t, "ByName.", doc.ByName[""].Path())
+
+	doc = Doc{}
+	require.NoError(t, Unmarshal([]byte(`{"Items":[{"Kind":"a","Body":1},null,]}`), &doc))
+	require.Len(t, doc.Items, 2)
+	require.Equal(t, "Items[0].Body", doc.Items[0].Body.Path())
+	require.Nil(t, doc.Items[1])
+	require.Error(t, Unmarshal([]byte(`{"Items":[{"Kind":"a"},]}`), &doc, WithMalformedPolicy(FailFast)))
+
+	type Keyed struct {
+		Id   int `jsonx:"key"`
+		Kind string
+		Body Raw
+	}
+	type KeyedDoc struct {
+		Items []Keyed
+	}
+	keyed := KeyedDoc{Items: []Keyed{{Id: 1, Kind: "a"}, {Id: 2, Kind: "b"}}}
+	require.NoError(t, Unmarshal([]byte(`{"Items":[{"Id":2,"Body":true}]}`), &keyed, WithMergeContainers(true)))
+	require.Len(t, keyed.Items, 2)
+	require.Equal(t, "b", keyed.Items[1].Kind)
+	require.Equal(t, KindBool, keyed.Items[1].Body.Kind())
+}
@@ -181,6 +181,9 @@ type Options struct {
 	scannerHooks       ScannerHooks
 	OmitEmpty          bool
 	NilSlicePolicy     NilSlicePolicy
+	Paths              []string
+	AliasInput         bool
+	MergeContainers    bool
 
 	setMode               bool
 	setUnknownFieldPolicy bool
@@ -0,0 +1,24 @@
+package json
+
+import (
+	"reflect"
+
+	"github.com/viant/structology/encoding/json/internal/union"
+)
+
+// RegisterUnion registers concrete types for an interface, keyed by the value of a discriminator field.
+// Interface-typed values (including slice and map elements) then unmarshal into the concrete type named
+// by the discriminator, and marshal writes the discriminator back. Types may be structs or struct pointers.
+//
+//	json.RegisterUnion(reflect.TypeOf((*Shape)(nil)).Elem(), "kind", map[string]reflect.Type{
+//		"circle": reflect.TypeOf(&Circle{}),
+//		"square": reflect.TypeOf(&Square{}),
+//	})
+func RegisterUnion(iface reflect.Type, discriminator string, types map[string]reflect.Type) error {
+	u, err := union.New(iface, discriminator, types)
+	if err != nil {
+		return err
+	}
+	union.Register(u)
+	return nil
+}
@@ -0,0 +1,80 @@
+package json
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type unionShape interface{ Area() float64 }
+
+type unionCircle struct {
+	Radius float64 `json:"radius"`
+}
+
+func (c *unionCircle) Area() float64 { return 3 * c.Radius * c.Radius }
+
+type unionSquare struct {
+	Kind string `json:"kind"`
+	Side float64
+}
+
+func (s unionSquare) Area() float64 { return s.Side * s.Side }
+
+type unionDrawing struct {
+	Main   unionShape
+	Shapes []unionShape
+	ByName map[string]unionShape
+}
+
+func init() {
+	if err := RegisterUnion(reflect.TypeOf((*unionShape)(nil)).Elem(), "kind", map[string]reflect.Type{
+		"circle": reflect.TypeOf(&unionCircle{}),
+		"square": reflect.TypeOf(unionSquare{}),
+	}); err != nil {
+		panic(err)
+	}
+}
+
+func TestRegisterUnion_Unmarshal(t *testing.T) {
+	data := []byte(`{"Main":{"kind":"circle","radius":1},"Shapes":[{"kind":"square","Side":2},{"kind":"circle","radius":2}],"ByName":{"a":{"kind":"circle","radius":3},"b":null}}`)
+	var drawing unionDrawing
+	require.NoError(t, Unmarshal(data, &drawing, WithUnknownFieldPolicy(ErrorOnUnknown)))
+	require.Equal(t, &unionCircle{Radius: 1}, drawing.Main)
+	require.Equal(t, []unionShape{unionSquare{Kind: "square", Side: 2}, &unionCircle{Radius: 2}}, drawing.Shapes)
+	require.Equal(t, &unionCircle{Radius: 3}, drawing.ByName["a"])
+	require.Nil(t, drawing.ByName["b"])
+
+	drawing = unionDrawing{}
+	require.NoError(t, Unmarshal([]byte(`{"Main":{"Kind":"circle","radius":1},"Shapes":[{"KIND":"square","Side":2}]}`), &drawing, WithUnknownFieldPolicy(ErrorOnUnknown)))
+	require.Equal(t, &unionCircle{Radius: 1}, drawing.Main)
+	require.Equal(t, []unionShape{unionSquare{Kind: "square", Side: 2}}, drawing.Shapes)
+}
+
+func TestRegisterUnion_UnmarshalErrors(t *testing.T) {
+	var drawing unionDrawing
+	require.ErrorContains(t, Unmarshal([]byte(`{"Main":{"kind":"hexagon"}}`), &drawing), `unknown kind discriminator "hexagon"`)
+	require.ErrorContains(t, Unmarshal([]byte(`{"Main":{"radius":1}}`), &drawing), "missing kind discriminator")
+}
+
+func TestRegisterUnion_MarshalWritesDiscriminator(t *testing.T) {
+	drawing := unionDrawing{
+		Main:   &unionCircle{Radius: 1},
+		Shapes: []unionShape{unionSquare{Kind: "square", Side: 2}, &unionCircle{}},
+	}
+	out, err := Marshal(drawing)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"Main":{"kind":"circle","radius":1},"Shapes":[{"kind":"square","Side":2},{"kind":"circle","radius":0}],"ByName":null}`, string(out))
+
+	var decoded unionDrawing
+	require.NoError(t, Unmarshal(out, &decoded))
+	require.Equal(t, drawing, decoded)
+}
+
+func TestRegisterUnion_Validation(t *testing.T) {
+	iface := reflect.TypeOf((*unionShape)(nil)).Elem()
+	require.Error(t, RegisterUnion(reflect.TypeOf(unionSquare{}), "kind", nil))
+	require.Error(t, RegisterUnion(iface, "", nil))
+	require.Error(t, RegisterUnion(iface, "kind", map[string]reflect.Type{"circle": reflect.TypeOf(unionCircle{})}))
+}
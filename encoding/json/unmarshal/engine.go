@@ -16,6 +16,7 @@ import (
 
 	"github.com/viant/structology/encoding/json/internal/lru"
 	"github.com/viant/structology/encoding/json/internal/tagutil"
+	"github.com/viant/structology/encoding/json/internal/union"
 	ftime "github.com/viant/tagly/format/time"
 	"github.com/viant/xunsafe"
 )
@@ -85,6 +86,12 @@ type Engine struct {
 	caseKey            string
 	compileName        func(string) string
 	PathHook           func(ctx context.Context, holder unsafe.Pointer, path []string, field string, value interface{}) (interface{}, error)
+	// Projection, when set, limits struct decoding to the projected paths; other subtrees are skipped.
+	Projection *Projection
+	// AliasInput lets registered span types reference the input buffer instead of copying their span.
+	AliasInput bool
+	// MergeContainers merges decoded keys into existing maps and patches existing struct slices instead of replacing them.
+	MergeContainers bool
 }
 
 func New(ctx context.Context, hooks ScannerHooks, unknown UnknownFieldPolicy, number NumberPolicy, nulls NullPolicy, duplicates DuplicateKeyPolicy, malformed MalformedPolicy, timeLayout string, caseKey string, compileName func(string) string, pathHook func(ctx context.Context, holder unsafe.Pointer, path []string, field string, value interface{}) (interface{}, error)) *Engine {
@@ -110,6 +117,19 @@ func (e *Engine) Unmarshal(data []byte, dest interface{}) error {
 	if dest == nil {
 		return fmt.Errorf("nil destination")
 	}
+	if rt := reflect.TypeOf(dest); rt != nil && rt.Kind() == reflect.Ptr {
+		if shape, assign := spanShapeOf(rt.Elem()); shape != spanNone {
+			d := &scalarDecoder{data: data, hooks: e.Hooks, malformedPolicy: e.MalformedPolicy}
+			if err := e.decodeSpanField(d, shape, assign, rt.Elem(), xunsafe.AsPointer(dest)); err != nil {
+				return err
+			}
+			d.skipWS()
+			if d.pos != len(d.data) {
+				return fmt.Errorf("unexpected trailing data at %d", d.pos)
+			}
+			return nil
+		}
+	}
 	if rt := reflect.TypeOf(dest); rt != nil && rt.Kind() == reflect.Ptr && rt.Elem() == timeType {
 		parsed, err := decodeJSON(data, e.Hooks, e.DuplicateKeyPolicy, e.MalformedPolicy)
 		if err != nil {
@@ -152,6 +172,8 @@ func (e *Engine) unmarshalStructFast(data []byte, ptr unsafe.Pointer, rType refl
 		hooks:              e.Hooks,
 		duplicateKeyPolicy: e.DuplicateKeyPolicy,
 		malformedPolicy:    e.MalformedPolicy,
+		projection:         e.Projection,
+		mergeContainers:    e.MergeContainers,
 	}
 	d.skipWS()
 	if d.pos >= len(d.data) || d.data[d.pos] != '{' {
@@ -182,6 +204,7 @@ func (e *Engine) unmarshalStructFromDecoder(d *scalarDecoder, structPtr unsafe.P
 	if plan.presence != nil {
 		_ = ensurePresenceHolder(structPtr, plan.presence)
 	}
+	projection := d.projection
 	d.skipWS()
 	if d.pos < len(d.data) && d.data[d.pos] == '}' {
 		d.pos++
@@ -212,24 +235,45 @@ func (e *Engine) unmarshalStructFromDecoder(d *scalarDecoder, structPtr unsafe.P
 			if e.UnknownFieldPolicy == ErrorOnUnknown {
 				return fmt.Errorf("unknown field %s", key)
 			}
-			if _, err = d.parseValue(); err != nil {
+			if err = d.skipIgnoredValue(projection); err != nil {
 				return err
 			}
 		} else if fp.ignore {
-			if _, err = d.parseValue(); err != nil {
+			if err = d.skipIgnoredValue(projection); err != nil {
+				return err
+			}
+		} else if sub, selected := projection.child(key, fp.name); !selected {
+			if err = d.skipRawValue(); err != nil {
 				return err
 			}
 		} else {
+			d.projection = sub
 			fieldPtr := fp.resolve(structPtr)
 			pathPushed := false
 			if e.PathHook != nil {
 				d.path = append(d.path, fp.name)
 				pathPushed = true
 			}
+			if plan.reachesSpan {
+				d.spanPath = append(d.spanPath, key)
+			}
 			hooksEnabled := e.PathHook != nil
 			customUnmarshal := fp.hasCustomUnmarshal && !isTimeTypeOrPtr(fp.rType)
 			streamNested := fp.rType.Kind() == reflect.Struct || (fp.rType.Kind() == reflect.Ptr && fp.rType.Elem().Kind() == reflect.Struct)
-			if hooksEnabled && streamNested && !customUnmarshal {
+			if fp.spanShape != spanNone || fp.spanStructSlice {
+				var spanErr error
+				if fp.spanShape != spanNone {
+					spanErr = e.decodeSpanField(d, fp.spanShape, fp.spanAssign, fp.rType, fieldPtr)
+				} else {
+					spanErr = e.decodeStructSliceWithSpans(d, fp.rType, fieldPtr)
+				}
+				if spanErr != nil {
+					if pathPushed && len(d.path) > 0 {
+						d.path = d.path[:len(d.path)-1]
+					}
+					return wrapPathError(key, spanErr)
+				}
+			} else if hooksEnabled && streamNested && !customUnmarshal {
 				if handled, decodeErr := e.tryDecodeTypedField(d, fp, fieldPtr); handled {
 					if pathPushed && len(d.path) > 0 {
 						d.path = d.path[:len(d.path)-1]
@@ -238,7 +282,7 @@ func (e *Engine) unmarshalStructFromDecoder(d *scalarDecoder, structPtr unsafe.P
 						return wrapPathError(key, decodeErr)
 					}
 				} else {
-					val, parseErr := d.parseValue()
+					val, parseErr := d.parseProjectedValue(sub)
 					if parseErr != nil {
 						if pathPushed && len(d.path) > 0 {
 							d.path = d.path[:len(d.path)-1]
@@ -253,7 +297,7 @@ func (e *Engine) unmarshalStructFromDecoder(d *scalarDecoder, structPtr unsafe.P
 					}
 				}
 			} else if hooksEnabled {
-				val, parseErr := d.parseValue()
+				val, parseErr := d.parseProjectedValue(sub)
 				if parseErr != nil {
 					if pathPushed && len(d.path) > 0 {
 						d.path = d.path[:len(d.path)-1]
@@ -317,7 +361,7 @@ func (e *Engine) unmarshalStructFromDecoder(d *scalarDecoder, structPtr unsafe.P
 					return wrapPathError(key, decodeErr)
 				}
 			} else {
-				val, parseErr := d.parseValue()
+				val, parseErr := d.parseProjectedValue(sub)
 				if parseErr != nil {
 					if pathPushed && len(d.path) > 0 {
 						d.path = d.path[:len(d.path)-1]
@@ -334,6 +378,10 @@ func (e *Engine) unmarshalStructFromDecoder(d *scalarDecoder, structPtr unsafe.P
 			if pathPushed && len(d.path) > 0 {
 				d.path = d.path[:len(d.path)-1]
 			}
+			if plan.reachesSpan {
+				d.spanPath = d.spanPath[:len(d.spanPath)-1]
+			}
+			d.projection = projection
 			if plan.presence != nil && fp.presenceFlag != nil {
 				h := ensurePresenceHolder(structPtr, plan.presence)
 				if h != nil {
@@ -552,7 +600,11 @@ type scalarDecoder struct {
 	pos   int
 	hooks ScannerHooks
 	path  []string
+	// spanPath tracks document keys while decoding types that can hold registered span types.
+	spanPath []string
 
+	projection         *Projection
+	mergeContainers    bool
 	duplicateKeyPolicy DuplicateKeyPolicy
 	malformedPolicy    MalformedPolicy
 }
@@ -579,9 +631,9 @@ func (d *scalarDecoder) parseValue() (interface{}, error) {
 	}
 	switch d.data[d.pos] {
 	case '{':
-		return d.parseObject()
+		return d.parseObject(nil)
 	case '[':
-		return d.parseArray()
+		return d.parseArray(nil)
 	case '"':
 		return d.parseString()
 	case 't':
@@ -777,7 +829,8 @@ func (d *scalarDecoder) match(token string) bool {
 	return true
 }
 
-func (d *scalarDecoder) parseObject() (map[string]interface{}, error) {
+// parseObject parses an object; a non-nil projection keeps only selected keys and skips the others.
+func (d *scalarDecoder) parseObject(projection *Projection) (map[string]interface{}, error) {
 	d.pos++
 	obj := make(map[string]interface{})
 	var seen map[string]struct{}
@@ -806,11 +859,21 @@ func (d *scalarDecoder) parseObject() (map[string]interface{}, error) {
 			return nil, fmt.Errorf("expected ':' at %d", d.pos)
 		}
 		d.pos++
-		val, err := d.parseValue()
-		if err != nil {
+		if projection == nil {
+			val, err := d.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = val
+		} else if sub, selected := projection.child(key, ""); selected {
+			val, err := d.parseProjectedValue(sub)
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = val
+		} else if err = d.skipRawValue(); err != nil {
 			return nil, err
 		}
-		obj[key] = val
 		d.skipWS()
 		if d.pos >= len(d.data) {
 			return nil, fmt.Errorf("unexpected EOF in object")
@@ -837,7 +900,8 @@ func (d *scalarDecoder) parseObject() (map[string]interface{}, error) {
 	}
 }
 
-func (d *scalarDecoder) parseArray() ([]interface{}, error) {
+// parseArray parses an array; a non-nil projection applies to every item.
+func (d *scalarDecoder) parseArray(projection *Projection) ([]interface{}, error) {
 	d.pos++
 	arr := make([]interface{}, 0)
 	d.skipWS()
@@ -846,7 +910,13 @@ func (d *scalarDecoder) parseArray() ([]interface{}, error) {
 		return arr, nil
 	}
 	for {
-		v, err := d.parseValue()
+		var v interface{}
+		var err error
+		if projection == nil {
+			v, err = d.parseValue()
+		} else {
+			v, err = d.parseProjectedValue(projection)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -1157,7 +1227,7 @@ func (d *scalarDecoder) parseStringMapInto(dst *map[string]string) error {
 	out := *dst
 	if out == nil {
 		out = make(map[string]string, 4)
-	} else {
+	} else if !d.mergeContainers {
 		for k := range out {
 			delete(out, k)
 		}
@@ -1458,6 +1528,7 @@ type typePlan struct {
 	fieldsByName map[string]*fieldPlan
 	fieldsByFold map[uint64][]foldField
 	presence     *presencePlan
+	reachesSpan  bool
 }
 
 type foldField struct {
@@ -1474,6 +1545,9 @@ type fieldPlan struct {
 	hasCustomUnmarshal bool
 	presenceFlag       *xunsafe.Field
 	resolve            func(root unsafe.Pointer) unsafe.Pointer
+	spanShape          spanShape
+	spanAssign         SpanAssigner
+	spanStructSlice    bool
 }
 
 type presencePlan struct {
@@ -1597,6 +1671,14 @@ func buildPlan(rType reflect.Type, compileName func(string) string) *typePlan {
 				hasCustomUnmarshal: hasCustomUnmarshalType(sf.Type),
 				resolve:            buildResolver(chain),
 			}
+			fp.spanShape, fp.spanAssign = spanShapeOf(sf.Type)
+			if fp.spanShape == spanNone && sf.Type.Kind() == reflect.Slice && !fp.hasCustomUnmarshal {
+				elem := sf.Type.Elem()
+				if elem.Kind() == reflect.Ptr {
+					elem = elem.Elem()
+				}
+				fp.spanStructSlice = elem.Kind() == reflect.Struct && elem != timeType && !hasCustomUnmarshalType(elem) && reachesSpan(elem)
+			}
 			addField(name, fp)
 			if compileName != nil && !explicit {
 				alias := compileName(name)
@@ -1608,6 +1690,7 @@ func buildPlan(rType reflect.Type, compileName func(string) string) *typePlan {
 	}
 
 	collect(rType, nil, true)
+	p.reachesSpan = reachesSpan(rType)
 	if p.presence != nil {
 		seen := map[*fieldPlan]struct{}{}
 		for _, fp := range p.fieldsByName {
@@ -1672,6 +1755,9 @@ func (e *Engine) assignPlannedField(ptr unsafe.Pointer, fp *fieldPlan, parsed in
 }
 
 func assignValue(ptr unsafe.Pointer, rt reflect.Type, parsed interface{}, e *Engine) error {
+	if assign, ok := spanAssignerFor(rt); ok {
+		return assignSpanFromParsed(ptr, assign, parsed)
+	}
 	if handled, err := tryAssignCustomUnmarshal(ptr, rt, parsed, e); handled || err != nil {
 		return err
 	}
@@ -1856,6 +1942,11 @@ func assignValue(ptr unsafe.Pointer, rt reflect.Type, parsed interface{}, e *Eng
 		*xunsafe.AsFloat64Ptr(ptr) = f
 		return nil
 	case reflect.Interface:
+		if rt.NumMethod() > 0 {
+			if u, ok := union.Lookup(rt); ok {
+				return assignUnion(ptr, u, parsed, e)
+			}
+		}
 		reflect.NewAt(rt, ptr).Elem().Set(reflect.ValueOf(parsed))
 		return nil
 	case reflect.Slice:
@@ -1867,6 +1958,11 @@ func assignValue(ptr unsafe.Pointer, rt reflect.Type, parsed interface{}, e *Eng
 		if !ok {
 			return fmt.Errorf("expected array")
 		}
+		if e.MergeContainers && isMergeableStruct(rt.Elem()) {
+			if current := reflect.NewAt(rt, ptr).Elem(); !current.IsNil() {
+				return assignMergedSlice(current, items, e)
+			}
+		}
 		slice := reflect.MakeSlice(rt, len(items), len(items))
 		elemType := rt.Elem()
 		for i := 0; i < len(items); i++ {
@@ -1912,6 +2008,11 @@ func assignValue(ptr unsafe.Pointer, rt reflect.Type, parsed interface{}, e *Eng
 		if rt.Key().Kind() != reflect.String {
 			return fmt.Errorf("unsupported map key kind: %s", rt.Key().Kind())
 		}
+		if e.MergeContainers {
+			if current := reflect.NewAt(rt, ptr).Elem(); !current.IsNil() {
+				return assignMergedMap(current, obj, e)
+			}
+		}
 		m := reflect.MakeMapWithSize(rt, len(obj))
 		elemType := rt.Elem()
 		for key, val := range obj {
@@ -1929,6 +2030,58 @@ func assignValue(ptr unsafe.Pointer, rt reflect.Type, parsed interface{}, e *Eng
 	return nil
 }
 
+// assignUnion decodes an object into the concrete union type selected by its discriminator.
+func assignUnion(ptr unsafe.Pointer, u *union.Union, parsed interface{}, e *Engine) error {
+	obj, ok := parsed.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected object for %s", u.Interface.String())
+	}
+	key := discriminatorKey(obj, u.Discriminator)
+	label, ok := obj[key].(string)
+	if !ok {
+		return fmt.Errorf("missing %s discriminator for %s", u.Discriminator, u.Interface.String())
+	}
+	concrete, ok := u.Types[label]
+	if !ok {
+		return fmt.Errorf("unknown %s discriminator %q for %s", u.Discriminator, label, u.Interface.String())
+	}
+	if !u.HasField(concrete) {
+		trimmed := make(map[string]interface{}, len(obj))
+		for name, val := range obj {
+			if name != key {
+				trimmed[name] = val
+			}
+		}
+		obj = trimmed
+	}
+	structType := concrete
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	value := reflect.New(structType)
+	if err := assignValue(unsafe.Pointer(value.Pointer()), structType, obj, e); err != nil {
+		return err
+	}
+	if concrete.Kind() != reflect.Ptr {
+		value = value.Elem()
+	}
+	reflect.NewAt(u.Interface, ptr).Elem().Set(value)
+	return nil
+}
+
+// discriminatorKey returns the object key holding the discriminator, matched case-insensitively like field names.
+func discriminatorKey(obj map[string]interface{}, discriminator string) string {
+	if _, ok := obj[discriminator]; ok {
+		return discriminator
+	}
+	for key := range obj {
+		if strings.EqualFold(key, discriminator) {
+			return key
+		}
+	}
+	return discriminator
+}
+
 func expectedObjectError(rt reflect.Type) error {
 	if rt.Kind() == reflect.Struct {
 		return fmt.Errorf("Cannot unmarshal JSON to type '*json.structDecoder'")
@@ -0,0 +1,160 @@
+package unmarshal
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+
+	"github.com/viant/structology/encoding/json/internal/tagutil"
+)
+
+var mergeKeys sync.Map // reflect.Type -> mergeKey
+
+type mergeKey struct {
+	index int
+	err   error
+}
+
+// mergeKeyIndex returns the index of the struct field tagged `jsonx:"key"`, or -1;
+// a key field of a non-comparable type is reported as an error.
+func mergeKeyIndex(rType reflect.Type) (int, error) {
+	if v, ok := mergeKeys.Load(rType); ok {
+		key := v.(mergeKey)
+		return key.index, key.err
+	}
+	key := mergeKey{index: -1}
+	for i := 0; i < rType.NumField(); i++ {
+		sf := rType.Field(i)
+		if sf.PkgPath == "" && tagutil.HasJSONXOption(sf.Tag, "key") {
+			key.index = i
+			if !sf.Type.Comparable() {
+				key.err = fmt.Errorf("merge key field %s.%s has non-comparable type %s", rType.Name(), sf.Name, sf.Type)
+			}
+			break
+		}
+	}
+	mergeKeys.Store(rType, key)
+	return key.index, key.err
+}
+
+func isMergeableStruct(rType reflect.Type) bool {
+	if rType.Kind() == reflect.Ptr {
+		rType = rType.Elem()
+	}
+	return rType.Kind() == reflect.Struct && rType != timeType
+}
+
+// assignMergedMap merges parsed keys into an existing map; struct values already present are patched in place.
+func assignMergedMap(mapValue reflect.Value, obj map[string]interface{}, e *Engine) error {
+	rt := mapValue.Type()
+	elemType := rt.Elem()
+	patch := isMergeableStruct(elemType)
+	for key, val := range obj {
+		mapKey := reflect.New(rt.Key()).Elem()
+		mapKey.SetString(key)
+		elem := reflect.New(elemType)
+		if patch {
+			if current := mapValue.MapIndex(mapKey); current.IsValid() {
+				elem.Elem().Set(current)
+			}
+		}
+		if err := assignValue(unsafe.Pointer(elem.Pointer()), elemType, val, e); err != nil {
+			return wrapPathError(key, err)
+		}
+		mapValue.SetMapIndex(mapKey, elem.Elem())
+	}
+	return nil
+}
+
+// assignMergedSlice patches an existing struct slice: items are matched by the `jsonx:"key"` field when the
+// element declares one, otherwise by index. Unmatched items are appended and existing elements are kept.
+func assignMergedSlice(sliceValue reflect.Value, items []interface{}, e *Engine) error {
+	elemType := sliceValue.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	keyIndex, err := mergeKeyIndex(structType)
+	if err != nil {
+		return err
+	}
+	var byKey map[interface{}]int
+	var keyPlan *fieldPlan
+	if keyIndex != -1 {
+		byKey = make(map[interface{}]int, sliceValue.Len())
+		for i := 0; i < sliceValue.Len(); i++ {
+			item := sliceValue.Index(i)
+			if item.Kind() == reflect.Ptr {
+				if item.IsNil() {
+					continue
+				}
+				item = item.Elem()
+			}
+			if key := item.Field(keyIndex); key.Comparable() {
+				byKey[key.Interface()] = i
+			}
+		}
+		keyPlan = planKeyField(structType, keyIndex, e)
+	}
+	result := sliceValue
+	for i, parsed := range items {
+		target := -1
+		if keyIndex == -1 {
+			if i < sliceValue.Len() {
+				target = i
+			}
+		} else if key, ok, err := parsedMergeKey(parsed, structType, keyPlan, e); err != nil {
+			return wrapPathError(fmt.Sprintf("[%d]", i), err)
+		} else if ok && reflect.ValueOf(key).Comparable() {
+			if index, found := byKey[key]; found {
+				target = index
+			}
+		}
+		elem := reflect.New(elemType)
+		if target != -1 {
+			elem.Elem().Set(result.Index(target))
+		}
+		if err := assignValue(unsafe.Pointer(elem.Pointer()), elemType, parsed, e); err != nil {
+			return wrapPathError(fmt.Sprintf("[%d]", i), err)
+		}
+		if target != -1 {
+			result.Index(target).Set(elem.Elem())
+			continue
+		}
+		result = reflect.Append(result, elem.Elem())
+	}
+	sliceValue.Set(result)
+	return nil
+}
+
+func planKeyField(structType reflect.Type, keyIndex int, e *Engine) *fieldPlan {
+	name := structType.Field(keyIndex).Name
+	for _, fp := range planFor(structType, e.caseKey, e.compileName).fieldsByName {
+		if fp.name == name {
+			return fp
+		}
+	}
+	return nil
+}
+
+// parsedMergeKey decodes the key field of a parsed object into the key field type.
+func parsedMergeKey(parsed interface{}, structType reflect.Type, keyPlan *fieldPlan, e *Engine) (interface{}, bool, error) {
+	obj, ok := parsed.(map[string]interface{})
+	if !ok || keyPlan == nil {
+		return nil, false, nil
+	}
+	plan := planFor(structType, e.caseKey, e.compileName)
+	for key, val := range obj {
+		fp, ok := lookupField(plan, key)
+		if !ok || fp != keyPlan {
+			continue
+		}
+		keyValue := reflect.New(fp.rType)
+		if err := assignValue(unsafe.Pointer(keyValue.Pointer()), fp.rType, val, e); err != nil {
+			return nil, false, err
+		}
+		return keyValue.Elem().Interface(), true, nil
+	}
+	return nil, false, nil
+}
@@ -0,0 +1,267 @@
+package unmarshal
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrPathNotFound is returned when a pointer does not address any value.
+var ErrPathNotFound = errors.New("path not found")
+
+var errExtractDone = errors.New("extract done")
+
+// pointerNode is a trie of pointer segments; targets lists result slots resolved at the node.
+type pointerNode struct {
+	fields  map[string]*pointerNode
+	indexes map[int]*pointerNode
+	targets []int
+}
+
+func (n *pointerNode) hasChildren() bool {
+	return len(n.fields) > 0 || len(n.indexes) > 0
+}
+
+func (n *pointerNode) add(segments []string, target int) {
+	node := n
+	for _, segment := range segments {
+		if node.fields == nil {
+			node.fields = map[string]*pointerNode{}
+		}
+		child, ok := node.fields[segment]
+		if !ok {
+			child = &pointerNode{}
+			node.fields[segment] = child
+			if index, ok := pointerIndex(segment); ok {
+				if node.indexes == nil {
+					node.indexes = map[int]*pointerNode{}
+				}
+				node.indexes[index] = child
+			}
+		}
+		node = child
+	}
+	node.targets = append(node.targets, target)
+}
+
+// pointerIndex parses an array index segment; RFC 6901 allows only 0 or digits without a leading zero or sign.
+func pointerIndex(segment string) (int, bool) {
+	if segment == "" || segment[0] == '0' && len(segment) > 1 {
+		return 0, false
+	}
+	for i := 0; i < len(segment); i++ {
+		if segment[i] < '0' || segment[i] > '9' {
+			return 0, false
+		}
+	}
+	index, err := strconv.Atoi(segment)
+	return index, err == nil
+}
+
+// ParsePointer splits a JSON Pointer (RFC 6901, for example "/items/3/name") or a dotted
+// path (for example "items[3].name", optionally prefixed with "$.") into unescaped segments.
+func ParsePointer(path string) ([]string, error) {
+	if path == "" || path == "$" {
+		return nil, nil
+	}
+	if path[0] == '/' {
+		parts := strings.Split(path[1:], "/")
+		for i, part := range parts {
+			if strings.IndexByte(part, '~') == -1 {
+				continue
+			}
+			var sb strings.Builder
+			for j := 0; j < len(part); j++ {
+				if part[j] != '~' {
+					sb.WriteByte(part[j])
+					continue
+				}
+				if j+1 >= len(part) {
+					return nil, fmt.Errorf("invalid pointer escape in %q", path)
+				}
+				switch part[j+1] {
+				case '0':
+					sb.WriteByte('~')
+				case '1':
+					sb.WriteByte('/')
+				default:
+					return nil, fmt.Errorf("invalid pointer escape in %q", path)
+				}
+				j++
+			}
+			parts[i] = sb.String()
+		}
+		return parts, nil
+	}
+	path = strings.TrimPrefix(path, "$")
+	path = strings.TrimPrefix(path, ".")
+	var segments []string
+	for _, part := range strings.Split(path, ".") {
+		for part != "" {
+			open := strings.IndexByte(part, '[')
+			if open == -1 {
+				segments = append(segments, part)
+				break
+			}
+			if open > 0 {
+				segments = append(segments, part[:open])
+			}
+			closing := strings.IndexByte(part[open:], ']')
+			if closing == -1 {
+				return nil, fmt.Errorf("invalid path %q: missing ']'", path)
+			}
+			segments = append(segments, strings.Trim(part[open+1:open+closing], `'"`))
+			part = part[open+closing+1:]
+		}
+	}
+	return segments, nil
+}
+
+// Extract scans data once and returns the raw spans addressed by paths, aligned with paths.
+// Missing paths yield nil spans. Spans alias data; the first occurrence of a duplicated key wins
+// and scanning stops as soon as every path is resolved, so input past that point is not validated.
+func Extract(data []byte, hooks ScannerHooks, malformed MalformedPolicy, paths ...string) ([][]byte, error) {
+	root := &pointerNode{}
+	for i, path := range paths {
+		segments, err := ParsePointer(path)
+		if err != nil {
+			return nil, err
+		}
+		root.add(segments, i)
+	}
+	result := make([][]byte, len(paths))
+	remaining := len(paths)
+	d := &scalarDecoder{data: data, hooks: hooks, malformedPolicy: malformed}
+	if err := d.extractValue(root, result, &remaining); err != nil && err != errExtractDone {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (d *scalarDecoder) extractValue(node *pointerNode, result [][]byte, remaining *int) error {
+	d.skipWS()
+	start := d.pos
+	if !node.hasChildren() || d.pos >= len(d.data) {
+		if err := d.skipRawValue(); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		switch d.data[d.pos] {
+		case '{':
+			err = d.extractObject(node, result, remaining)
+		case '[':
+			err = d.extractArray(node, result, remaining)
+		default:
+			err = d.skipRawValue()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, target := range node.targets {
+		if result[target] == nil {
+			result[target] = d.data[start:d.pos]
+			*remaining--
+		}
+	}
+	if *remaining == 0 {
+		return errExtractDone
+	}
+	return nil
+}
+
+func (d *scalarDecoder) extractObject(node *pointerNode, result [][]byte, remaining *int) error {
+	d.pos++
+	d.skipWS()
+	if d.pos < len(d.data) && d.data[d.pos] == '}' {
+		d.pos++
+		return nil
+	}
+	for {
+		key, err := d.parseKey()
+		if err != nil {
+			return err
+		}
+		d.skipWS()
+		if d.pos >= len(d.data) || d.data[d.pos] != ':' {
+			return fmt.Errorf("expected ':' at %d", d.pos)
+		}
+		d.pos++
+		if child, ok := node.fields[key]; ok {
+			err = d.extractValue(child, result, remaining)
+		} else {
+			err = d.skipRawValue()
+		}
+		if err != nil {
+			return err
+		}
+		d.skipWS()
+		if d.pos >= len(d.data) {
+			return fmt.Errorf("unexpected EOF in object")
+		}
+		if d.data[d.pos] == '}' {
+			d.pos++
+			return nil
+		}
+		if d.data[d.pos] != ',' {
+			if d.malformedPolicy == Tolerant && d.data[d.pos] == '"' {
+				continue
+			}
+			return fmt.Errorf("expected ',' at %d", d.pos)
+		}
+		d.pos++
+		if d.malformedPolicy == Tolerant {
+			d.skipWS()
+			if d.pos < len(d.data) && d.data[d.pos] == '}' {
+				d.pos++
+				return nil
+			}
+		}
+	}
+}
+
+func (d *scalarDecoder) extractArray(node *pointerNode, result [][]byte, remaining *int) error {
+	d.pos++
+	d.skipWS()
+	if d.pos < len(d.data) && d.data[d.pos] == ']' {
+		d.pos++
+		return nil
+	}
+	for index := 0; ; index++ {
+		var err error
+		if child, ok := node.indexes[index]; ok {
+			err = d.extractValue(child, result, remaining)
+		} else {
+			err = d.skipRawValue()
+		}
+		if err != nil {
+			return err
+		}
+		d.skipWS()
+		if d.pos >= len(d.data) {
+			return fmt.Errorf("unexpected EOF in array")
+		}
+		if d.data[d.pos] == ']' {
+			d.pos++
+			return nil
+		}
+		if d.data[d.pos] != ',' {
+			return fmt.Errorf("expected ',' at %d", d.pos)
+		}
+		d.pos++
+		if d.malformedPolicy == Tolerant {
+			d.skipWS()
+			if d.pos < len(d.data) && d.data[d.pos] == ']' {
+				d.pos++
+				return nil
+			}
+		}
+	}
+}
+
+// DecodeValue decodes a raw JSON span into its generic representation.
+func DecodeValue(data []byte, hooks ScannerHooks) (interface{}, error) {
+	return decodeJSON(data, hooks, LastWins, Tolerant)
+}
@@ -0,0 +1,97 @@
+package unmarshal
+
+import "strings"
+
+// Projection restricts decoding to a set of dotted field paths.
+// Subtrees outside the projection are skipped without being materialized.
+type Projection struct {
+	children map[string]*Projection
+}
+
+// NewProjection compiles dotted paths (for example "Tenant.Id") into a projection tree.
+// Path segments match JSON keys or struct field names, case-insensitively; map keys are matched the same way
+// and slice items share the projection of their slice.
+func NewProjection(paths ...string) *Projection {
+	if len(paths) == 0 {
+		return nil
+	}
+	root := &Projection{}
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		node := root
+		for _, segment := range strings.Split(path, ".") {
+			if segment == "" {
+				continue
+			}
+			key := strings.ToLower(segment)
+			if node.children == nil {
+				node.children = map[string]*Projection{}
+			}
+			child, ok := node.children[key]
+			if !ok {
+				child = &Projection{}
+				node.children[key] = child
+			}
+			node = child
+		}
+	}
+	if len(root.children) == 0 {
+		return nil
+	}
+	return root
+}
+
+// IsLeaf returns true when the whole subtree under the node is selected.
+func (p *Projection) IsLeaf() bool {
+	return p == nil || len(p.children) == 0
+}
+
+// child returns the sub-projection matching a JSON key or a struct field name.
+// A nil sub-projection with ok=true means the full subtree is selected.
+func (p *Projection) child(key, fieldName string) (*Projection, bool) {
+	if p == nil {
+		return nil, true
+	}
+	node, ok := p.children[strings.ToLower(key)]
+	if !ok && fieldName != "" && fieldName != key {
+		node, ok = p.children[strings.ToLower(fieldName)]
+	}
+	if !ok {
+		return nil, false
+	}
+	if node.IsLeaf() {
+		return nil, true
+	}
+	return node, true
+}
+
+// parseProjectedValue parses a value keeping only the object keys selected by projection;
+// array items share the projection of the array. A nil projection parses the whole value.
+func (d *scalarDecoder) parseProjectedValue(projection *Projection) (interface{}, error) {
+	if projection == nil {
+		return d.parseValue()
+	}
+	d.skipWS()
+	if d.pos < len(d.data) {
+		switch d.data[d.pos] {
+		case '{':
+			return d.parseObject(projection)
+		case '[':
+			return d.parseArray(projection)
+		}
+	}
+	return d.parseValue()
+}
+
+// skipIgnoredValue consumes the value of an unknown or ignored field; under a projection it is
+// skipped without being materialized.
+func (d *scalarDecoder) skipIgnoredValue(projection *Projection) error {
+	if projection != nil {
+		return d.skipRawValue()
+	}
+	_, err := d.parseValue()
+	return err
+}
@@ -0,0 +1,110 @@
+package unmarshal
+
+// Scanner exposes the byte-level JSON scanner used by the engine to sibling codecs.
+// Token readers skip leading whitespace; raw spans alias the input.
+type Scanner struct {
+	d scalarDecoder
+}
+
+// NewScanner creates a scanner over data; nil hooks select plain byte loops.
+func NewScanner(data []byte, hooks ScannerHooks) *Scanner {
+	if hooks == nil {
+		hooks = plainHooks{}
+	}
+	return &Scanner{d: scalarDecoder{data: data, hooks: hooks, malformedPolicy: FailFast}}
+}
+
+// Pos returns the current offset.
+func (s *Scanner) Pos() int { return s.d.pos }
+
+// EOF reports whether only whitespace remains.
+func (s *Scanner) EOF() bool {
+	s.d.skipWS()
+	return s.d.pos >= len(s.d.data)
+}
+
+// Peek returns the next non-whitespace byte, or 0 at the end of input.
+func (s *Scanner) Peek() byte {
+	s.d.skipWS()
+	if s.d.pos >= len(s.d.data) {
+		return 0
+	}
+	return s.d.data[s.d.pos]
+}
+
+// Consume advances past c when it is the next non-whitespace byte.
+func (s *Scanner) Consume(c byte) bool {
+	if s.Peek() != c || c == 0 {
+		return false
+	}
+	s.d.pos++
+	return true
+}
+
+// Null consumes a null literal when present.
+func (s *Scanner) Null() bool {
+	if s.Peek() != 'n' {
+		return false
+	}
+	return s.d.match("null")
+}
+
+// String reads a string token.
+func (s *Scanner) String() (string, error) {
+	s.d.skipWS()
+	return s.d.parseStringValue()
+}
+
+// RawValue returns the span of the next value.
+func (s *Scanner) RawValue() ([]byte, error) {
+	s.d.skipWS()
+	return s.d.parseRawValue()
+}
+
+// Skip validates and skips the next value.
+func (s *Scanner) Skip() error {
+	s.d.skipWS()
+	return s.d.skipRawValue()
+}
+
+// Value parses the next value into its generic representation.
+func (s *Scanner) Value() (interface{}, error) {
+	s.d.skipWS()
+	return s.d.parseValue()
+}
+
+type plainHooks struct{}
+
+func (plainHooks) SkipWhitespace(data []byte, pos int) int {
+	for pos < len(data) {
+		switch data[pos] {
+		case ' ', '\n', '\r', '\t':
+			pos++
+		default:
+			return pos
+		}
+	}
+	return pos
+}
+
+func (plainHooks) FindQuoteOrEscape(data []byte, pos int) (int, int) {
+	for i := pos; i < len(data); i++ {
+		if data[i] == '"' {
+			return i, -1
+		}
+		if data[i] == '\\' {
+			return -1, i
+		}
+	}
+	return -1, -1
+}
+
+func (plainHooks) FindStructural(data []byte, pos int) int {
+	for i := pos; i < len(data); i++ {
+		switch data[i] {
+		case '{', '}', '[', ']', ':', ',':
+			return i
+		}
+	}
+	return -1
+}
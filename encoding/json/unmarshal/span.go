@@ -0,0 +1,338 @@
+package unmarshal
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// SpanAssigner stores a raw JSON value span at ptr; path is the location of the value in the document.
+type SpanAssigner func(ptr unsafe.Pointer, span []byte, path string)
+
+type spanShape uint8
+
+const (
+	spanNone spanShape = iota
+	spanValue
+	spanPtr
+	spanSlice
+	spanMap
+)
+
+var (
+	spanTypes sync.Map // reflect.Type -> SpanAssigner
+	spanReach sync.Map // reflect.Type -> bool
+)
+
+// RegisterSpanType registers a type that captures raw value spans instead of being decoded.
+// Fields of that type, pointers to it, slices of it and string-keyed maps of it receive spans
+// directly from the scanner. Register before the first decode of any type using it.
+func RegisterSpanType(rType reflect.Type, assign SpanAssigner) {
+	spanTypes.Store(rType, assign)
+	spanReach.Range(func(key, _ interface{}) bool {
+		spanReach.Delete(key)
+		return true
+	})
+}
+
+func spanAssignerFor(rType reflect.Type) (SpanAssigner, bool) {
+	v, ok := spanTypes.Load(rType)
+	if !ok {
+		return nil, false
+	}
+	return v.(SpanAssigner), true
+}
+
+// spanShapeOf returns how rType holds span values, with the assigner of its span element type.
+func spanShapeOf(rType reflect.Type) (spanShape, SpanAssigner) {
+	if assign, ok := spanAssignerFor(rType); ok {
+		return spanValue, assign
+	}
+	var shape spanShape
+	switch rType.Kind() {
+	case reflect.Ptr:
+		shape = spanPtr
+	case reflect.Slice:
+		shape = spanSlice
+	case reflect.Map:
+		if rType.Key().Kind() != reflect.String {
+			return spanNone, nil
+		}
+		shape = spanMap
+	default:
+		return spanNone, nil
+	}
+	if assign, ok := spanAssignerFor(rType.Elem()); ok {
+		return shape, assign
+	}
+	return spanNone, nil
+}
+
+// reachesSpan reports whether values of rType can hold a registered span type.
+func reachesSpan(rType reflect.Type) bool {
+	if v, ok := spanReach.Load(rType); ok {
+		return v.(bool)
+	}
+	result := typeReachesSpan(rType, map[reflect.Type]bool{})
+	spanReach.Store(rType, result)
+	return result
+}
+
+func typeReachesSpan(rType reflect.Type, visited map[reflect.Type]bool) bool {
+	if _, ok := spanAssignerFor(rType); ok {
+		return true
+	}
+	if visited[rType] {
+		return false
+	}
+	visited[rType] = true
+	switch rType.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return typeReachesSpan(rType.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < rType.NumField(); i++ {
+			if sf := rType.Field(i); sf.PkgPath == "" && typeReachesSpan(sf.Type, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// spanBytes returns the span as stored in the destination: aliased when AliasInput is set, copied otherwise.
+func (e *Engine) spanBytes(raw []byte) []byte {
+	if e.AliasInput {
+		return raw
+	}
+	return append([]byte(nil), raw...)
+}
+
+func (d *scalarDecoder) spanPathString() string {
+	var sb strings.Builder
+	for _, segment := range d.spanPath {
+		if sb.Len() > 0 && (segment == "" || segment[0] != '[') {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(segment)
+	}
+	return sb.String()
+}
+
+func (e *Engine) decodeSpanField(d *scalarDecoder, shape spanShape, assign SpanAssigner, rt reflect.Type, ptr unsafe.Pointer) error {
+	d.skipWS()
+	if shape != spanValue && d.pos < len(d.data) && d.data[d.pos] == 'n' && d.match("null") {
+		reflect.NewAt(rt, ptr).Elem().SetZero()
+		return nil
+	}
+	switch shape {
+	case spanValue:
+		raw, err := d.parseRawValue()
+		if err != nil {
+			return err
+		}
+		assign(ptr, e.spanBytes(raw), d.spanPathString())
+		return nil
+	case spanPtr:
+		raw, err := d.parseRawValue()
+		if err != nil {
+			return err
+		}
+		target := *(*unsafe.Pointer)(ptr)
+		if target == nil {
+			target = unsafe.Pointer(reflect.New(rt.Elem()).Pointer())
+			*(*unsafe.Pointer)(ptr) = target
+		}
+		assign(target, e.spanBytes(raw), d.spanPathString())
+		return nil
+	case spanSlice:
+		if d.pos >= len(d.data) || d.data[d.pos] != '[' {
+			return fmt.Errorf("expected '[' at %d", d.pos)
+		}
+		d.pos++
+		slice := reflect.MakeSlice(rt, 0, 4)
+		d.skipWS()
+		if d.pos < len(d.data) && d.data[d.pos] == ']' {
+			d.pos++
+			reflect.NewAt(rt, ptr).Elem().Set(slice)
+			return nil
+		}
+		for index := 0; ; index++ {
+			raw, err := d.parseRawValue()
+			if err != nil {
+				return err
+			}
+			item := reflect.New(rt.Elem())
+			d.spanPath = append(d.spanPath, "["+strconv.Itoa(index)+"]")
+			assign(unsafe.Pointer(item.Pointer()), e.spanBytes(raw), d.spanPathString())
+			d.spanPath = d.spanPath[:len(d.spanPath)-1]
+			slice = reflect.Append(slice, item.Elem())
+			d.skipWS()
+			if d.pos >= len(d.data) {
+				return fmt.Errorf("unexpected EOF in array")
+			}
+			if d.data[d.pos] == ']' {
+				d.pos++
+				break
+			}
+			if d.data[d.pos] != ',' {
+				return fmt.Errorf("expected ',' at %d", d.pos)
+			}
+			d.pos++
+			if d.malformedPolicy == Tolerant {
+				d.skipWS()
+				if d.pos < len(d.data) && d.data[d.pos] == ']' {
+					d.pos++
+					break
+				}
+			}
+		}
+		reflect.NewAt(rt, ptr).Elem().Set(slice)
+		return nil
+	case spanMap:
+		if d.pos >= len(d.data) || d.data[d.pos] != '{' {
+			return fmt.Errorf("expected '{' at %d", d.pos)
+		}
+		d.pos++
+		mapValue := reflect.NewAt(rt, ptr).Elem()
+		if mapValue.IsNil() {
+			mapValue.Set(reflect.MakeMap(rt))
+		}
+		d.skipWS()
+		if d.pos < len(d.data) && d.data[d.pos] == '}' {
+			d.pos++
+			return nil
+		}
+		for {
+			key, err := d.parseStringValue()
+			if err != nil {
+				return err
+			}
+			d.skipWS()
+			if d.pos >= len(d.data) || d.data[d.pos] != ':' {
+				return fmt.Errorf("expected ':' at %d", d.pos)
+			}
+			d.pos++
+			raw, err := d.parseRawValue()
+			if err != nil {
+				return err
+			}
+			item := reflect.New(rt.Elem())
+			d.spanPath = append(d.spanPath, key)
+			assign(unsafe.Pointer(item.Pointer()), e.spanBytes(raw), d.spanPathString())
+			d.spanPath = d.spanPath[:len(d.spanPath)-1]
+			mapValue.SetMapIndex(reflect.ValueOf(key).Convert(rt.Key()), item.Elem())
+			d.skipWS()
+			if d.pos >= len(d.data) {
+				return fmt.Errorf("unexpected EOF in object")
+			}
+			if d.data[d.pos] == '}' {
+				d.pos++
+				return nil
+			}
+			if d.data[d.pos] != ',' {
+				return fmt.Errorf("expected ',' at %d", d.pos)
+			}
+			d.pos++
+			if d.malformedPolicy == Tolerant {
+				d.skipWS()
+				if d.pos < len(d.data) && d.data[d.pos] == '}' {
+					d.pos++
+					return nil
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// decodeStructSliceWithSpans streams a slice of structs (or struct pointers) whose elements hold span types,
+// so that nested spans keep their element path. Slices merged into with MergeContainers take the generic path.
+func (e *Engine) decodeStructSliceWithSpans(d *scalarDecoder, rt reflect.Type, ptr unsafe.Pointer) error {
+	d.skipWS()
+	sliceValue := reflect.NewAt(rt, ptr).Elem()
+	if d.pos < len(d.data) && d.data[d.pos] == 'n' && d.match("null") {
+		sliceValue.SetZero()
+		return nil
+	}
+	if e.MergeContainers && !sliceValue.IsNil() {
+		parsed, err := d.parseValue()
+		if err != nil {
+			return err
+		}
+		return assignValue(ptr, rt, parsed, e)
+	}
+	if d.pos >= len(d.data) || d.data[d.pos] != '[' {
+		return fmt.Errorf("expected '[' at %d", d.pos)
+	}
+	d.pos++
+	elemType := rt.Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	slice := reflect.MakeSlice(rt, 0, 4)
+	d.skipWS()
+	if d.pos < len(d.data) && d.data[d.pos] == ']' {
+		d.pos++
+		sliceValue.Set(slice)
+		return nil
+	}
+	for index := 0; ; index++ {
+		d.skipWS()
+		if d.pos < len(d.data) && d.data[d.pos] == 'n' && d.match("null") {
+			if elemType.Kind() != reflect.Ptr && e.NullPolicy == StrictNulls {
+				return wrapPathError(strconv.Itoa(index), fmt.Errorf("null is not allowed for %s", elemType.String()))
+			}
+			slice = reflect.Append(slice, reflect.Zero(elemType))
+		} else {
+			item := reflect.New(structType)
+			d.spanPath = append(d.spanPath, "["+strconv.Itoa(index)+"]")
+			err := e.unmarshalStructFromDecoder(d, unsafe.Pointer(item.Pointer()), structType)
+			d.spanPath = d.spanPath[:len(d.spanPath)-1]
+			if err != nil {
+				return wrapPathError(strconv.Itoa(index), err)
+			}
+			if elemType.Kind() == reflect.Ptr {
+				slice = reflect.Append(slice, item)
+			} else {
+				slice = reflect.Append(slice, item.Elem())
+			}
+		}
+		d.skipWS()
+		if d.pos >= len(d.data) {
+			return fmt.Errorf("unexpected EOF in array")
+		}
+		if d.data[d.pos] == ']' {
+			d.pos++
+			break
+		}
+		if d.data[d.pos] != ',' {
+			return fmt.Errorf("expected ',' at %d", d.pos)
+		}
+		d.pos++
+		if d.malformedPolicy == Tolerant {
+			d.skipWS()
+			if d.pos < len(d.data) && d.data[d.pos] == ']' {
+				d.pos++
+				break
+			}
+		}
+	}
+	sliceValue.Set(slice)
+	return nil
+}
+
+// assignSpanFromParsed stores an already parsed value into a span type by re-encoding it.
+// It is used by the generic assignment path, where the original span is no longer available.
+func assignSpanFromParsed(ptr unsafe.Pointer, assign SpanAssigner, parsed interface{}) error {
+	raw, err := stdjson.Marshal(parsed)
+	if err != nil {
+		return err
+	}
+	assign(ptr, raw, "")
+	return nil
+}
@@ -26,14 +26,10 @@ func MarshalContext(ctx context.Context, value interface{}, opts ...Option) ([]b
 		return defaultMarshalEngine.Marshal(value)
 	}
 	cfg := resolveOptions(ctx, opts)
-	caseKey := ""
-	var compileName func(string) string
-	if cfg.CaseFormat != "" {
-		tr := caseFormatTransformer{caseFormat: cfg.CaseFormat}
-		caseKey = string(cfg.CaseFormat)
-		compileName = func(field string) string { return tr.Transform(field) }
+	m := newMarshalEngine(cfg)
+	if cfg.OutputFormat != JSONTable {
+		return m.MarshalDelimited(nil, value, delimitedFormat(cfg))
 	}
-	m := jsmarshal.New(cfg.TagName, caseKey, compileName, cfg.TimeLayout, cfg.FloatPrecision)
 	return m.Marshal(value)
 }
 
@@ -45,14 +41,41 @@ func UnmarshalContext(ctx context.Context, data []byte, dest interface{}, opts .
 	if len(opts) == 0 {
 		return defaultUnmarshalEngine.Unmarshal(data, dest)
 	}
-	cfg := resolveOptions(ctx, opts)
-	caseKey := ""
-	var compileName func(string) string
-	if cfg.CaseFormat != "" {
-		tr := caseFormatTransformer{caseFormat: cfg.CaseFormat}
-		caseKey = string(cfg.CaseFormat)
-		compileName = func(field string) string { return tr.Transform(field) }
+	u := newUnmarshalEngine(resolveOptions(ctx, opts))
+	return u.Unmarshal(data, dest)
+}
+
+func Unmarshal(data []byte, dest interface{}, opts ...Option) error {
+	return UnmarshalContext(context.Background(), data, dest, opts...)
+}
+
+func caseFormatNames(cfg Options) (string, func(string) string) {
+	if cfg.CaseFormat == "" {
+		return "", nil
 	}
+	tr := caseFormatTransformer{caseFormat: cfg.CaseFormat}
+	return string(cfg.CaseFormat), func(field string) string { return tr.Transform(field) }
+}
+
+func newMarshalEngine(cfg Options) *jsmarshal.Engine {
+	caseKey, compileName := caseFormatNames(cfg)
+	ret := jsmarshal.New(cfg.TagName, caseKey, compileName, cfg.TimeLayout, cfg.FloatPrecision)
+	ret.Columns = cfg.Columns
+	ret.TypedHeaders = cfg.TypedHeaders
+	if cfg.Layout == ColumnarLayout {
+		ret.Layout = jsmarshal.ColumnarLayout
+	}
+	switch cfg.Presence {
+	case PresenceNull:
+		ret.Presence = jsmarshal.PresenceNull
+	case PresenceOmit:
+		ret.Presence = jsmarshal.PresenceOmit
+	}
+	return ret
+}
+
+func newUnmarshalEngine(cfg Options) *jsunmarshal.Engine {
+	caseKey, compileName := caseFormatNames(cfg)
 	unknown := jsunmarshal.IgnoreUnknownHeader
 	if cfg.UnknownHeaderPolicy == ErrorOnUnknownHeader {
 		unknown = jsunmarshal.ErrorOnUnknownHeader
@@ -65,12 +88,25 @@ func UnmarshalContext(ctx context.Context, data []byte, dest interface{}, opts .
 	if cfg.MalformedPolicy == ErrorOnMalformed {
 		malformed = jsunmarshal.ErrorOnMalformed
 	}
-	u := jsunmarshal.New(cfg.TagName, caseKey, compileName, cfg.TimeLayout, unknown, arity, malformed)
-	return u.Unmarshal(data, dest)
+	ret := jsunmarshal.New(cfg.TagName, caseKey, compileName, cfg.TimeLayout, unknown, arity, malformed)
+	ret.Delimiter = cfg.Delimiter
+	ret.Columns = cfg.Columns
+	ret.NullUnset = cfg.Presence != PresenceIgnore
+	return ret
 }
 
-func Unmarshal(data []byte, dest interface{}, opts ...Option) error {
-	return UnmarshalContext(context.Background(), data, dest, opts...)
+func delimitedFormat(cfg Options) jsmarshal.Delimited {
+	ret := jsmarshal.Delimited{Delimiter: cfg.Delimiter}
+	switch cfg.Quoting {
+	case QuoteAll:
+		ret.Quote = jsmarshal.QuoteAll
+	case QuoteNone:
+		ret.Quote = jsmarshal.QuoteNone
+	}
+	if cfg.SlicePolicy == SliceOmit {
+		ret.Slices = jsmarshal.SliceOmit
+	}
+	return ret
 }
 
 func normalizeTagName(tagName string) string {
@@ -0,0 +1,115 @@
+package jsontab
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// cellMoney is a struct that encodes itself as a JSON string with pointer receivers.
+type cellMoney struct {
+	Cents int64
+}
+
+func (m *cellMoney) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%d.%02d", m.Cents/100, m.Cents%100))
+}
+
+func (m *cellMoney) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	units, cents, _ := strings.Cut(text, ".")
+	u, err := strconv.ParseInt(units, 10, 64)
+	if err != nil {
+		return err
+	}
+	c, err := strconv.ParseInt(cents, 10, 64)
+	if err != nil {
+		return err
+	}
+	m.Cents = u*100 + c
+	return nil
+}
+
+// cellLevel is an integer written as its name.
+type cellLevel int
+
+func (l cellLevel) MarshalText() ([]byte, error) {
+	return []byte([]string{"low", "high"}[l]), nil
+}
+
+func (l *cellLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*l = 0
+	case "high":
+		*l = 1
+	default:
+		return fmt.Errorf("invalid level %q", text)
+	}
+	return nil
+}
+
+type cellRec struct {
+	ID     int                    `csvName:"id"`
+	Attrs  map[string]interface{} `csvName:"attrs"`
+	Tags   []string               `csvName:"tags"`
+	Any    interface{}            `csvName:"any"`
+	Raw    json.RawMessage        `csvName:"raw"`
+	Price  cellMoney              `csvName:"price"`
+	Level  cellLevel              `csvName:"level"`
+	Backup *cellLevel             `csvName:"backup"`
+}
+
+func cellRows() []cellRec {
+	high := cellLevel(1)
+	return []cellRec{
+		{ID: 1, Attrs: map[string]interface{}{"a": 1.0, "b": []interface{}{"x"}}, Tags: []string{"x", "y"}, Any: "text",
+			Raw: json.RawMessage(`{"n":12345678901234567890}`), Price: cellMoney{Cents: 150}, Level: 1, Backup: &high},
+		{ID: 2, Any: map[string]interface{}{"k": true}, Price: cellMoney{Cents: 5}},
+	}
+}
+
+func TestMarshal_CellEncodings(t *testing.T) {
+	data, err := Marshal(cellRows())
+	require.NoError(t, err)
+	require.Equal(t, `[["id","attrs","tags","any","raw","price","level","backup"],`+
+		`[1,{"a":1,"b":["x"]},["x","y"],"text",{"n":12345678901234567890},"1.50","high","high"],`+
+		`[2,null,null,{"k":true},null,"0.05","low",null]]`, string(data))
+
+	var out []cellRec
+	require.NoError(t, Unmarshal(data, &out, WithMode(ModeStrict)))
+	require.Equal(t, cellRows(), out)
+
+	typed, err := Marshal(cellRows()[:1], WithTypedHeaders(true))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(typed), `[["id:int","attrs:json","tags:json","any:json","raw:json","price:json","level:string","backup:string"]`))
+}
+
+func TestMarshal_CellEncodingsDelimited(t *testing.T) {
+	data, err := Marshal(cellRows(), WithOutputFormat(CSV))
+	require.NoError(t, err)
+	require.Equal(t, "id,attrs,tags,any,raw,price,level,backup\n"+
+		`1,"{""a"":1,""b"":[""x""]}","[""x"",""y""]",text,"{""n"":12345678901234567890}",1.50,high,high`+"\n"+
+		`2,,,"{""k"":true}",,0.05,low,`+"\n", string(data))
+
+	var out []cellRec
+	require.NoError(t, Unmarshal(data, &out, WithMode(ModeStrict)))
+	require.Equal(t, cellRows(), out)
+
+	data, err = Marshal(cellRows(), WithLayout(ColumnarLayout))
+	require.NoError(t, err)
+	out = nil
+	require.NoError(t, Unmarshal(data, &out))
+	require.Equal(t, cellRows(), out)
+
+	err = Unmarshal([]byte("id,level\n1,medium\n"), &out, WithMode(ModeStrict))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid level "medium"`)
+}
@@ -0,0 +1,105 @@
+package jsontab
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type columnarItem struct {
+	SKU string `csvName:"sku"`
+}
+
+type columnarRec struct {
+	ID     int            `csvName:"id"`
+	Status string         `csvName:"status"`
+	Region *string        `csvName:"region"`
+	Note   string         `csvName:"note"`
+	Items  []columnarItem `csvName:"items"`
+}
+
+func TestMarshal_ColumnarLayout(t *testing.T) {
+	eu := "eu"
+	rows := []*columnarRec{
+		{ID: 1, Status: "active", Region: &eu, Note: "a", Items: []columnarItem{{SKU: "x"}}},
+		{ID: 2, Status: "closed", Region: &eu, Note: "b"},
+		nil,
+		{ID: 3, Status: "active", Note: "c"},
+		{ID: 4, Status: "active", Region: &eu, Note: "d"},
+	}
+	data, err := Marshal(rows, WithLayout(ColumnarLayout))
+	require.NoError(t, err)
+	require.Equal(t, `{"headers":["id","status","region","note","items"],`+
+		`"dict":{"status":["active","closed"],"region":["eu"]},`+
+		`"columns":[[1,2,3,4],[0,1,0,0],[0,0,null,0],["a","b","c","d"],[[["sku"],["x"]],null,null,null]]}`, string(data))
+
+	var out []*columnarRec
+	require.NoError(t, Unmarshal(data, &out, WithMode(ModeStrict)))
+	require.Len(t, out, 4)
+	require.Equal(t, rows[0], out[0])
+	require.Equal(t, rows[1], out[1])
+	require.Equal(t, rows[3], out[2])
+	require.Equal(t, rows[4], out[3])
+
+	var single columnarRec
+	require.NoError(t, Unmarshal(data, &single))
+	require.Equal(t, *rows[0], single)
+
+	var projected []columnarRec
+	require.NoError(t, Unmarshal(data, &projected, WithColumns("status")))
+	require.Equal(t, []columnarRec{{Status: "active"}, {Status: "closed"}, {Status: "active"}, {Status: "active"}}, projected)
+}
+
+func TestMarshal_ColumnarEdgeCases(t *testing.T) {
+	data, err := Marshal([]columnarItem{}, WithLayout(ColumnarLayout))
+	require.NoError(t, err)
+	require.Equal(t, `{"headers":["sku"],"columns":[[]]}`, string(data))
+	var out []columnarItem
+	require.NoError(t, Unmarshal(data, &out))
+	require.Empty(t, out)
+
+	data, err = Marshal(columnarItem{SKU: "s"}, WithLayout(ColumnarLayout))
+	require.NoError(t, err)
+	require.Equal(t, `{"headers":["sku"],"columns":[["s"]]}`, string(data))
+
+	err = Unmarshal([]byte(`{"headers":["sku"],"dict":{"sku":["a"]},"columns":[[0,3]]}`), &out, WithMode(ModeStrict))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid dictionary index")
+
+	err = Unmarshal([]byte(`{"headers":["id","status"],"columns":[[1,2],["a"]]}`), &[]columnarRec{}, WithMode(ModeStrict))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "arity mismatch")
+
+	require.Error(t, NewDecoder(strings.NewReader(`{"headers":[]}`)).Next(&columnarItem{}))
+}
+
+type columnarPresenceHas struct {
+	ID     bool
+	Status bool
+}
+
+type columnarPresenceRec struct {
+	ID     int                  `csvName:"id"`
+	Status string               `csvName:"status"`
+	Has    *columnarPresenceHas `setMarker:"true"`
+}
+
+func TestUnmarshal_ColumnarNullPresence(t *testing.T) {
+	columnar := []byte(`{"headers":["id","status"],"dict":{"status":["a"]},"columns":[[1,2],[0,null]]}`)
+	rows := []byte(`[["id","status"],[1,"a"],[2,null]]`)
+	for _, data := range [][]byte{columnar, rows} {
+		var out []columnarPresenceRec
+		require.NoError(t, Unmarshal(data, &out))
+		require.Len(t, out, 2)
+		require.True(t, out[1].Has.Status, string(data))
+
+		out = nil
+		require.NoError(t, Unmarshal(data, &out, WithPresence(PresenceNull)))
+		require.True(t, out[0].Has.Status, string(data))
+		require.False(t, out[1].Has.Status, string(data))
+	}
+
+	_, err := Marshal([]interface{}{columnarItem{SKU: "a"}, columnarRec{ID: 1}}, WithLayout(ColumnarLayout))
+	require.ErrorContains(t, err, "does not match header type")
+}
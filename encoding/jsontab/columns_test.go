@@ -0,0 +1,67 @@
+package jsontab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type columnsChild struct {
+	ID   int    `csvName:"id"`
+	Name string `csvName:"name"`
+}
+
+type columnsHas struct {
+	ID       bool
+	Name     bool
+	Children bool
+}
+
+type columnsRec struct {
+	ID       int            `csvName:"id"`
+	Name     string         `csvName:"name"`
+	Note     string         `csvName:"note"`
+	Children []columnsChild `csvName:"children"`
+	Has      *columnsHas    `setMarker:"true"`
+}
+
+func TestMarshal_WithColumns(t *testing.T) {
+	rows := []columnsRec{{ID: 1, Name: "a", Note: "n", Children: []columnsChild{{ID: 10, Name: "c"}}}}
+	data, err := Marshal(rows, WithColumns("name", "children.id", "id"))
+	require.NoError(t, err)
+	require.Equal(t, `[["name","children","id"],["a",[["id"],[10]],1]]`, string(data))
+
+	data, err = Marshal(rows, WithColumns("children", "id"))
+	require.NoError(t, err)
+	require.Equal(t, `[["children","id"],[[["id","name"],[10,"c"]],1]]`, string(data))
+
+	data, err = Marshal(rows, WithColumns("id", "children.name"), WithOutputFormat(CSV))
+	require.NoError(t, err)
+	require.Equal(t, "id,children\n1,\"[[\"\"name\"\"],[\"\"c\"\"]]\"\n", string(data))
+
+	_, err = Marshal(rows, WithColumns("id", "children.missing"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unknown column "missing"`)
+}
+
+func TestUnmarshal_WithColumns(t *testing.T) {
+	data := []byte(`[["id","name","note","children"],[1,"a","n",[["id","name"],[10,"c"]]]]`)
+	var out []columnsRec
+	require.NoError(t, Unmarshal(data, &out, WithColumns("id", "children.name"), WithMode(ModeStrict)))
+	require.Len(t, out, 1)
+	require.Equal(t, 1, out[0].ID)
+	require.Empty(t, out[0].Name)
+	require.Empty(t, out[0].Note)
+	require.Equal(t, []columnsChild{{Name: "c"}}, out[0].Children)
+	require.True(t, out[0].Has.ID)
+	require.False(t, out[0].Has.Name)
+	require.True(t, out[0].Has.Children)
+
+	out = nil
+	require.NoError(t, Unmarshal([]byte("id,name,note\n1,a,n\n"), &out, WithColumns("note")))
+	require.Equal(t, []columnsRec{{Note: "n", Has: &columnsHas{}}}, out)
+
+	err := Unmarshal([]byte(`[["id","bogus"],[1,2]]`), &out, WithColumns("id"), WithMode(ModeStrict))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown header")
+}
@@ -0,0 +1,140 @@
+package jsontab
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type delimitedHas struct {
+	ID      bool
+	Address bool
+}
+
+type delimitedGeo struct {
+	Lat float64 `csvName:"lat"`
+}
+
+type delimitedAddress struct {
+	City string        `csvName:"city"`
+	Geo  *delimitedGeo `csvName:"geo"`
+}
+
+type delimitedItem struct {
+	SKU string `csvName:"sku"`
+}
+
+type delimitedRec struct {
+	ID      int               `csvName:"id"`
+	Name    string            `csvName:"name"`
+	Address *delimitedAddress `csvName:"address"`
+	Items   []delimitedItem   `csvName:"items"`
+	Has     *delimitedHas     `setMarker:"true"`
+}
+
+func delimitedRows() []*delimitedRec {
+	return []*delimitedRec{
+		{ID: 1, Name: "a, b", Address: &delimitedAddress{City: "Paris", Geo: &delimitedGeo{Lat: 48.5}}, Items: []delimitedItem{{SKU: "x"}}},
+		{ID: 2, Name: `say "hi"`},
+	}
+}
+
+func TestMarshal_CSVFlattensStructChildren(t *testing.T) {
+	data, err := Marshal(delimitedRows(), WithOutputFormat(CSV))
+	require.NoError(t, err)
+	require.Equal(t, "id,name,address.city,address.geo.lat,items\n"+
+		`1,"a, b",Paris,48.5,"[[""sku""],[""x""]]"`+"\n"+
+		`2,"say ""hi""",,,`+"\n", string(data))
+
+	var out []*delimitedRec
+	require.NoError(t, Unmarshal(data, &out))
+	require.Len(t, out, 2)
+	require.Equal(t, "a, b", out[0].Name)
+	require.Equal(t, &delimitedAddress{City: "Paris", Geo: &delimitedGeo{Lat: 48.5}}, out[0].Address)
+	require.Equal(t, []delimitedItem{{SKU: "x"}}, out[0].Items)
+	require.True(t, out[0].Has.Address)
+	require.Nil(t, out[1].Address)
+	require.False(t, out[1].Has.Address)
+	require.Equal(t, `say "hi"`, out[1].Name)
+
+	var table []delimitedRec
+	require.NoError(t, Unmarshal([]byte(`[["id","address.city"],[1,"Rome"],[2,null]]`), &table))
+	require.Equal(t, "Rome", table[0].Address.City)
+	require.Nil(t, table[1].Address)
+}
+
+func TestMarshal_TSVQuotingAndSlicePolicy(t *testing.T) {
+	data, err := Marshal(delimitedRows(), WithOutputFormat(TSV), WithSlicePolicy(SliceOmit))
+	require.NoError(t, err)
+	require.Equal(t, "id\tname\taddress.city\taddress.geo.lat\n"+
+		"1\ta, b\tParis\t48.5\n"+
+		"2\t\"say \"\"hi\"\"\"\t\t\n", string(data))
+
+	var out []delimitedRec
+	require.NoError(t, Unmarshal(data, &out, WithDelimiter('\t')))
+	require.Len(t, out, 2)
+	require.Equal(t, "a, b", out[0].Name)
+	require.Equal(t, "Paris", out[0].Address.City)
+	require.Nil(t, out[1].Address)
+
+	data, err = Marshal(delimitedRows()[:1], WithOutputFormat(CSV), WithDelimiter(';'), WithQuoting(QuoteAll), WithSlicePolicy(SliceOmit))
+	require.NoError(t, err)
+	require.Equal(t, `"id";"name";"address.city";"address.geo.lat"`+"\n"+`"1";"a, b";"Paris";"48.5"`+"\n", string(data))
+
+	_, err = Marshal(delimitedRows(), WithOutputFormat(CSV), WithQuoting(QuoteNone))
+	require.Error(t, err)
+	data, err = Marshal(delimitedRows(), WithOutputFormat(TSV), WithQuoting(QuoteNone), WithSlicePolicy(SliceOmit))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "2\tsay \"hi\"\t\t\n")
+}
+
+func TestMarshal_DelimitedRoots(t *testing.T) {
+	data, err := Marshal([]delimitedItem{}, WithOutputFormat(CSV))
+	require.NoError(t, err)
+	require.Equal(t, "sku\n", string(data))
+
+	data, err = Marshal(delimitedItem{SKU: " lead"}, WithOutputFormat(CSV))
+	require.NoError(t, err)
+	require.Equal(t, "sku\n\" lead\"\n", string(data))
+
+	data, err = Marshal([]interface{}{nil, delimitedItem{SKU: "a"}, 3, &delimitedItem{SKU: "b"}}, WithOutputFormat(CSV))
+	require.NoError(t, err)
+	require.Equal(t, "sku\na\nb\n", string(data))
+
+	_, err = Marshal([]interface{}{delimitedItem{SKU: "a"}, delimitedGeo{Lat: 1}}, WithOutputFormat(CSV))
+	require.ErrorContains(t, err, "does not match header type")
+}
+
+func TestMarshal_DelimitedInterfaceCells(t *testing.T) {
+	type cell struct {
+		Value interface{} `csvName:"value"`
+	}
+	rows := []cell{{Value: "123"}, {Value: 123.0}, {Value: "abc"}, {Value: "true"}, {Value: ""}}
+	data, err := Marshal(rows, WithOutputFormat(CSV))
+	require.NoError(t, err)
+	require.Equal(t, "value\n\"\"\"123\"\"\"\n123\nabc\n\"\"\"true\"\"\"\n\"\"\"\"\"\"\n", string(data))
+
+	var decoded []cell
+	require.NoError(t, Unmarshal(data, &decoded))
+	require.Equal(t, rows, decoded)
+}
+
+func TestDecoder_FlattenedTSVStream(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, WithOutputFormat(TSV))
+	for _, row := range delimitedRows() {
+		require.NoError(t, enc.WriteRow(row))
+	}
+	require.NoError(t, enc.Close())
+
+	dec := NewDecoder(&buf, WithDelimiter('\t'))
+	var row delimitedRec
+	require.NoError(t, dec.Next(&row))
+	require.Equal(t, 48.5, row.Address.Geo.Lat)
+	require.Equal(t, []delimitedItem{{SKU: "x"}}, row.Items)
+	require.NoError(t, dec.Next(&row))
+	require.Nil(t, row.Address)
+	require.Equal(t, io.EOF, dec.Next(&row))
+}
@@ -0,0 +1,54 @@
+package plan
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+)
+
+// CellEncoding selects how a scalar field is written to and read from a single cell.
+type CellEncoding int
+
+const (
+	// CellValue holds strings, numbers, bools and times.
+	CellValue CellEncoding = iota
+	// CellJSON holds a nested JSON value: maps, non-struct slices, arrays, interfaces and json.Marshaler types.
+	CellJSON
+	// CellText holds the string form of an encoding.TextMarshaler.
+	CellText
+)
+
+var (
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// cellEncoding classifies rType, with pointers already removed; marshalers take precedence over the kind.
+func cellEncoding(rType reflect.Type) CellEncoding {
+	if rType == timeType {
+		return CellValue
+	}
+	switch {
+	case implements(rType, jsonMarshalerType), implements(rType, jsonUnmarshalerType):
+		return CellJSON
+	case implements(rType, textMarshalerType), implements(rType, textUnmarshalerType):
+		return CellText
+	}
+	switch rType.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Interface, reflect.Struct:
+		return CellJSON
+	}
+	return CellValue
+}
+
+// isMarshaler reports whether rType controls its own JSON or text form.
+func isMarshaler(rType reflect.Type) bool {
+	return implements(rType, jsonMarshalerType) || implements(rType, jsonUnmarshalerType) ||
+		implements(rType, textMarshalerType) || implements(rType, textUnmarshalerType)
+}
+
+func implements(rType, iface reflect.Type) bool {
+	return rType.Implements(iface) || reflect.PointerTo(rType).Implements(iface)
+}
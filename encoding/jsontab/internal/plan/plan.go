@@ -25,7 +25,9 @@ type Field struct {
 	XField     *xunsafe.Field
 	Type       reflect.Type
 	Kind       FieldKind
-	Child      *Type
+	// Cell is the encoding of FieldScalar values.
+	Cell  CellEncoding
+	Child *Type
 }
 
 type Type struct {
@@ -36,6 +38,8 @@ type Type struct {
 	Children      []*Field
 	HeaderToField map[string]*Field
 	Presence      *PresencePlan
+	// Source is the unprojected plan of a column projection.
+	Source *Type
 }
 
 type PresencePlan struct {
@@ -131,19 +135,22 @@ func compile(rType reflect.Type, tagName string, compileName func(string) string
 			t = t.Elem()
 		}
 		switch {
-		case t.Kind() == reflect.Struct && t != timeType:
+		case t.Kind() == reflect.Struct && t != timeType && !isMarshaler(t):
 			field.Kind = FieldStruct
 			field.Child = compile(t, tagName, compileName, seen)
-		case sf.Type.Kind() == reflect.Slice:
+		case sf.Type.Kind() == reflect.Slice && !isMarshaler(sf.Type):
 			elem := sf.Type.Elem()
 			for elem.Kind() == reflect.Ptr {
 				elem = elem.Elem()
 			}
-			if elem.Kind() == reflect.Struct && elem != timeType {
+			if elem.Kind() == reflect.Struct && elem != timeType && !isMarshaler(elem) {
 				field.Kind = FieldSliceStruct
 				field.Child = compile(elem, tagName, compileName, seen)
 			}
 		}
+		if field.Kind == FieldScalar {
+			field.Cell = cellEncoding(t)
+		}
 
 		ret.Fields = append(ret.Fields, field)
 		ret.Headers = append(ret.Headers, header)
@@ -177,3 +184,15 @@ func EnsurePresenceHolder(ptr unsafe.Pointer, p *PresencePlan) unsafe.Pointer {
 	}
 	return holderPtr
 }
+
+// PresenceHolder returns the marker holder of the row at ptr without allocating; nil means presence is unknown.
+func PresenceHolder(ptr unsafe.Pointer, p *PresencePlan) unsafe.Pointer {
+	if p == nil || p.Holder == nil {
+		return nil
+	}
+	holderPtr := p.Holder.Pointer(ptr)
+	if p.HolderType.Kind() == reflect.Ptr {
+		return *(*unsafe.Pointer)(holderPtr)
+	}
+	return holderPtr
+}
@@ -0,0 +1,101 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/viant/structology/encoding/jsontab/internal/lru"
+)
+
+type projectionKey struct {
+	t       *Type
+	columns string
+}
+
+// projections caches derived plans; it is bounded because column lists are caller supplied.
+var projections = lru.New[projectionKey, *Type](1024)
+
+// Project derives a plan restricted to columns, in the given order.
+// Dotted columns such as `children.id` select columns of child tables; a bare child header selects the whole child.
+func Project(t *Type, columns []string) (*Type, error) {
+	if len(columns) == 0 {
+		return t, nil
+	}
+	key := projectionKey{t: t, columns: strings.Join(columns, "\x1f")}
+	if v, ok := projections.Get(key); ok {
+		return v, nil
+	}
+	compiled, err := project(t, columns)
+	if err != nil {
+		return nil, err
+	}
+	projections.Set(key, compiled)
+	return compiled, nil
+}
+
+type selection struct {
+	field  *Field
+	header string
+	whole  bool
+	nested []string
+}
+
+func project(t *Type, columns []string) (*Type, error) {
+	var selected []*selection
+	index := map[string]*selection{}
+	for _, column := range columns {
+		header, rest, ok := splitColumn(t, column)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+		sel := index[header]
+		if sel == nil {
+			sel = &selection{field: t.HeaderToField[header], header: header}
+			index[header] = sel
+			selected = append(selected, sel)
+		}
+		if rest == "" {
+			sel.whole = true
+		} else {
+			sel.nested = append(sel.nested, rest)
+		}
+	}
+	ret := &Type{Type: t.Type, HeaderToField: map[string]*Field{}, Presence: t.Presence, Source: t}
+	for _, sel := range selected {
+		field := sel.field
+		if !sel.whole && len(sel.nested) > 0 {
+			child, err := Project(field.Child, sel.nested)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", sel.header, err)
+			}
+			projected := *field
+			projected.Child = child
+			field = &projected
+		}
+		ret.Fields = append(ret.Fields, field)
+		ret.Headers = append(ret.Headers, sel.header)
+		ret.HeaderToField[sel.header] = field
+		if field.Kind == FieldScalar {
+			ret.ScalarFields = append(ret.ScalarFields, field)
+		} else {
+			ret.Children = append(ret.Children, field)
+		}
+	}
+	return ret, nil
+}
+
+// splitColumn splits column into a header of t and the remainder selecting child columns.
+func splitColumn(t *Type, column string) (string, string, bool) {
+	if _, ok := t.HeaderToField[column]; ok {
+		return column, "", true
+	}
+	for i := 0; i < len(column); i++ {
+		if column[i] != '.' {
+			continue
+		}
+		if f, ok := t.HeaderToField[column[:i]]; ok && f.Child != nil {
+			return column[:i], column[i+1:], true
+		}
+	}
+	return "", "", false
+}
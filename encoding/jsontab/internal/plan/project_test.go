@@ -0,0 +1,35 @@
+package plan
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProject_CacheIsBounded(t *testing.T) {
+	type row struct {
+		ID   int    `csvName:"id"`
+		Name string `csvName:"name"`
+	}
+	base := For(reflect.TypeOf(row{}), "csvName", "", func(s string) string { return s })
+
+	first, err := Project(base, []string{"name", "id"})
+	require.NoError(t, err)
+	again, err := Project(base, []string{"name", "id"})
+	require.NoError(t, err)
+	require.Same(t, first, again)
+	require.Equal(t, []string{"name", "id"}, first.Headers)
+
+	for i := 0; i < 4096; i++ {
+		columns := make([]string, 12)
+		for j := range columns {
+			if columns[j] = "id"; i&(1<<j) != 0 {
+				columns[j] = "name"
+			}
+		}
+		_, err = Project(base, columns)
+		require.NoError(t, err)
+	}
+	require.LessOrEqual(t, projections.Len(), 1024)
+}
@@ -0,0 +1,68 @@
+package plan
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Header type annotations written as `name:type`.
+const (
+	TypeString = "string"
+	TypeInt    = "int"
+	TypeFloat  = "float"
+	TypeBool   = "bool"
+	TypeTime   = "time"
+	TypeTable  = "table"
+	TypeJSON   = "json"
+)
+
+// TypeName returns the header type annotation of f.
+func (f *Field) TypeName() string {
+	if f.Kind != FieldScalar {
+		return TypeTable
+	}
+	rType := f.Type
+	for rType.Kind() == reflect.Ptr {
+		rType = rType.Elem()
+	}
+	if rType == timeType {
+		return TypeTime
+	}
+	switch f.Cell {
+	case CellJSON:
+		return TypeJSON
+	case CellText:
+		return TypeString
+	}
+	switch rType.Kind() {
+	case reflect.String:
+		return TypeString
+	case reflect.Bool:
+		return TypeBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return TypeInt
+	case reflect.Float32, reflect.Float64:
+		return TypeFloat
+	}
+	return TypeJSON
+}
+
+// SplitTypedHeader splits a `name:type` header; headers without a known type annotation are returned unchanged.
+func SplitTypedHeader(header string) (string, string) {
+	i := strings.LastIndexByte(header, ':')
+	if i < 0 {
+		return header, ""
+	}
+	switch typeName := header[i+1:]; typeName {
+	case TypeString, TypeInt, TypeFloat, TypeBool, TypeTime, TypeTable, TypeJSON:
+		return header[:i], typeName
+	}
+	return header, ""
+}
+
+// CompatibleType reports whether a declared header type can decode into f.
+func CompatibleType(declared string, f *Field) bool {
+	actual := f.TypeName()
+	return declared == actual || declared == TypeInt && actual == TypeFloat || actual == TypeJSON
+}
@@ -0,0 +1,181 @@
+package marshal
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"unsafe"
+
+	"github.com/viant/structology/encoding/jsontab/internal/plan"
+)
+
+// Layout selects how JSON tables arrange cells.
+type Layout int
+
+const (
+	// RowLayout writes `[[headers],[row]...]`.
+	RowLayout Layout = iota
+	// ColumnarLayout writes `{"headers":[...],"dict":{...},"columns":[[...],...]}`,
+	// replacing values of repeated string columns with indexes into per-column dictionaries.
+	ColumnarLayout
+)
+
+// appendColumnar writes a struct, struct slice or interface slice in the columnar layout.
+func (e *Engine) appendColumnar(dst []byte, rv reflect.Value) ([]byte, error) {
+	var rowType reflect.Type
+	var rows []unsafe.Pointer
+	switch rv.Kind() {
+	case reflect.Struct:
+		_, ptr := addressableStruct(rv)
+		rowType, rows = rv.Type(), []unsafe.Pointer{ptr}
+	case reflect.Slice:
+		for i := 0; i < rv.Len(); i++ {
+			item := deref(rv.Index(i))
+			if !item.IsValid() || item.Kind() != reflect.Struct {
+				continue
+			}
+			if rowType == nil {
+				rowType = item.Type()
+			} else if item.Type() != rowType {
+				return nil, fmt.Errorf("row %d type %s does not match header type %s", i, item.Type(), rowType)
+			}
+			_, ptr := addressableStruct(item)
+			rows = append(rows, ptr)
+		}
+		if rowType == nil {
+			if rowType = rv.Type().Elem(); rowType.Kind() == reflect.Ptr {
+				rowType = rowType.Elem()
+			}
+			if rowType.Kind() != reflect.Struct {
+				return append(dst, '[', ']'), nil
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported root kind: %s", rv.Kind())
+	}
+	t, err := e.planFor(rowType)
+	if err != nil {
+		return nil, err
+	}
+	if t, err = e.omitUnset(t, func(visit func(rowPtr unsafe.Pointer)) {
+		for _, row := range rows {
+			visit(row)
+		}
+	}); err != nil {
+		return nil, err
+	}
+	tp := e.tablePlanFor(t)
+
+	dicts := make([]*columnDict, len(tp.fields))
+	hasDict := false
+	for i, f := range tp.fields {
+		if dicts[i] = tp.buildDict(i, f, rows); dicts[i] != nil {
+			hasDict = true
+		}
+	}
+
+	dst = append(dst, `{"headers":`...)
+	dst = append(dst, tp.header...)
+	if hasDict {
+		dst = append(dst, `,"dict":{`...)
+		first := true
+		for i, dict := range dicts {
+			if dict == nil {
+				continue
+			}
+			if !first {
+				dst = append(dst, ',')
+			}
+			first = false
+			dst = appendString(dst, e.headerName(t, i))
+			dst = append(dst, ':', '[')
+			for j, value := range dict.values {
+				if j > 0 {
+					dst = append(dst, ',')
+				}
+				dst = appendString(dst, value)
+			}
+			dst = append(dst, ']')
+		}
+		dst = append(dst, '}')
+	}
+	dst = append(dst, `,"columns":[`...)
+	for i, op := range tp.ops {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, '[')
+		for j, row := range rows {
+			if j > 0 {
+				dst = append(dst, ',')
+			}
+			if tp.checks != nil && tp.checks[i] != nil && tp.checks[i].unset(row) {
+				dst = append(dst, "null"...)
+				continue
+			}
+			fieldPtr := tp.fields[i].XField.Pointer(row)
+			if dict := dicts[i]; dict != nil {
+				if value, ok := dictString(fieldPtr, tp.fields[i].Type); ok {
+					dst = strconv.AppendInt(dst, int64(dict.index[value]), 10)
+				} else {
+					dst = append(dst, "null"...)
+				}
+				continue
+			}
+			if dst, err = op(dst, fieldPtr); err != nil {
+				return nil, err
+			}
+		}
+		dst = append(dst, ']')
+	}
+	return append(dst, ']', '}'), nil
+}
+
+// columnDict holds the distinct values of a dictionary-encoded column in first-seen order.
+type columnDict struct {
+	values []string
+	index  map[string]int
+}
+
+// buildDict dictionary-encodes plain string columns whose values repeat at least twice on average.
+func (p *tablePlan) buildDict(i int, f *plan.Field, rows []unsafe.Pointer) *columnDict {
+	if f.Kind != plan.FieldScalar || f.Cell != plan.CellValue || !isDictType(f.Type) || len(rows) < 2 {
+		return nil
+	}
+	dict := &columnDict{index: map[string]int{}}
+	count := 0
+	for _, row := range rows {
+		if p.checks != nil && p.checks[i] != nil && p.checks[i].unset(row) {
+			continue
+		}
+		value, ok := dictString(f.XField.Pointer(row), f.Type)
+		if !ok {
+			continue
+		}
+		count++
+		if _, ok = dict.index[value]; !ok {
+			dict.index[value] = len(dict.values)
+			dict.values = append(dict.values, value)
+		}
+	}
+	if count == 0 || 2*len(dict.values) > count {
+		return nil
+	}
+	return dict
+}
+
+func isDictType(rType reflect.Type) bool {
+	if rType.Kind() == reflect.Ptr {
+		rType = rType.Elem()
+	}
+	return rType.Kind() == reflect.String
+}
+
+func dictString(ptr unsafe.Pointer, rType reflect.Type) (string, bool) {
+	if rType.Kind() == reflect.Ptr {
+		if ptr = *(*unsafe.Pointer)(ptr); ptr == nil {
+			return "", false
+		}
+	}
+	return *(*string)(ptr), true
+}
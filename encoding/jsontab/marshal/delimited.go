@@ -0,0 +1,315 @@
+package marshal
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/viant/structology/encoding/jsontab/internal/plan"
+)
+
+// Quote controls quoting of delimited cells.
+type Quote int
+
+const (
+	// QuoteMinimal quotes cells containing the delimiter, quotes, line breaks or leading spaces.
+	QuoteMinimal Quote = iota
+	// QuoteAll quotes every cell.
+	QuoteAll
+	// QuoteNone writes cells verbatim and fails on cells that would need quoting.
+	QuoteNone
+)
+
+// SlicePolicy controls struct slice children in delimited output.
+type SlicePolicy int
+
+const (
+	// SliceAsTable writes the child rows as a JSON table cell.
+	SliceAsTable SlicePolicy = iota
+	// SliceOmit drops struct slice columns.
+	SliceOmit
+)
+
+// Delimited configures CSV/TSV output; struct children are flattened into dotted headers.
+type Delimited struct {
+	Delimiter rune
+	Quote     Quote
+	Slices    SlicePolicy
+}
+
+func (d Delimited) delimiter() rune {
+	if d.Delimiter == 0 {
+		return ','
+	}
+	return d.Delimiter
+}
+
+// flatColumn reads one leaf field reached through struct parents.
+type flatColumn struct {
+	parents []*plan.Field
+	field   *plan.Field
+	op      cellOp
+	// checks hold marker checks for each parent and the leaf; nil unless a presence mode applies.
+	checks []*presenceCheck
+}
+
+type flatPlan struct {
+	headers []string
+	columns []flatColumn
+}
+
+type flatPlanKey struct {
+	tablePlanKey
+	slices SlicePolicy
+}
+
+var flatPlans sync.Map // map[flatPlanKey]*flatPlan
+
+func (e *Engine) flatPlanFor(rowType reflect.Type, slices SlicePolicy) (*flatPlan, error) {
+	t, err := e.planFor(rowType)
+	if err != nil {
+		return nil, err
+	}
+	return e.flatPlanOf(t, slices), nil
+}
+
+func (e *Engine) flatPlanOf(t *plan.Type, slices SlicePolicy) *flatPlan {
+	key := flatPlanKey{tablePlanKey: e.tablePlanKey(t), slices: slices}
+	if v, ok := flatPlans.Load(key); ok {
+		return v.(*flatPlan)
+	}
+	compiled := &flatPlan{}
+	e.flatten(compiled, t, "", nil, nil, slices)
+	actual, _ := flatPlans.LoadOrStore(key, compiled)
+	return actual.(*flatPlan)
+}
+
+func (e *Engine) flatten(dst *flatPlan, t *plan.Type, prefix string, parents []*plan.Field, checks []*presenceCheck, slices SlicePolicy) {
+	tp := e.tablePlanFor(t)
+	for i, f := range t.Fields {
+		header := prefix + t.Headers[i]
+		fieldChecks := append(checks[:len(checks):len(checks)], e.presenceCheck(t, f))
+		switch f.Kind {
+		case plan.FieldStruct:
+			e.flatten(dst, f.Child, header+".", append(parents[:len(parents):len(parents)], f), fieldChecks, slices)
+			continue
+		case plan.FieldSliceStruct:
+			if slices == SliceOmit {
+				continue
+			}
+		}
+		column := flatColumn{parents: parents, field: f, op: tp.text[i]}
+		for _, check := range fieldChecks {
+			if check != nil {
+				column.checks = fieldChecks
+				break
+			}
+		}
+		if e.TypedHeaders {
+			header += ":" + f.TypeName()
+		}
+		dst.headers = append(dst.headers, header)
+		dst.columns = append(dst.columns, column)
+	}
+}
+
+func (p *flatPlan) appendRow(dst []byte, ptr unsafe.Pointer, format Delimited) ([]byte, error) {
+	var err error
+	delimiter := format.delimiter()
+	for i := range p.columns {
+		if i > 0 {
+			dst = utf8.AppendRune(dst, delimiter)
+		}
+		column := &p.columns[i]
+		owner := column.owner(ptr)
+		if owner != nil && column.checks != nil && column.checks[len(column.parents)] != nil && column.checks[len(column.parents)].unset(owner) {
+			owner = nil
+		}
+		start := len(dst)
+		if owner != nil {
+			if dst, err = column.op(dst, column.field.XField.Pointer(owner)); err != nil {
+				return nil, err
+			}
+		}
+		if dst, err = format.quote(dst, start); err != nil {
+			return nil, err
+		}
+	}
+	return append(dst, '\n'), nil
+}
+
+// owner returns the struct holding the leaf field, or nil when a parent is nil or unset.
+func (c *flatColumn) owner(ptr unsafe.Pointer) unsafe.Pointer {
+	for j, parent := range c.parents {
+		if c.checks != nil && c.checks[j] != nil && c.checks[j].unset(ptr) {
+			return nil
+		}
+		if ptr = derefStruct(parent.XField.Pointer(ptr), parent.Type); ptr == nil {
+			return nil
+		}
+	}
+	return ptr
+}
+
+func (p *flatPlan) appendHeader(dst []byte, format Delimited) ([]byte, error) {
+	var err error
+	for i, header := range p.headers {
+		if i > 0 {
+			dst = utf8.AppendRune(dst, format.delimiter())
+		}
+		start := len(dst)
+		dst = append(dst, header...)
+		if dst, err = format.quote(dst, start); err != nil {
+			return nil, err
+		}
+	}
+	return append(dst, '\n'), nil
+}
+
+// derefStruct follows pointer levels of a struct field, returning nil for nil pointers.
+func derefStruct(ptr unsafe.Pointer, rType reflect.Type) unsafe.Pointer {
+	for ; rType.Kind() == reflect.Ptr; rType = rType.Elem() {
+		if ptr = *(*unsafe.Pointer)(ptr); ptr == nil {
+			return nil
+		}
+	}
+	return ptr
+}
+
+// quote applies the quoting policy to the cell written at dst[start:].
+func (d Delimited) quote(dst []byte, start int) ([]byte, error) {
+	cell := dst[start:]
+	switch d.Quote {
+	case QuoteNone:
+		if bytes.ContainsRune(cell, d.delimiter()) || bytes.ContainsAny(cell, "\r\n") {
+			return nil, fmt.Errorf("cell %q requires quoting", cell)
+		}
+		return dst, nil
+	case QuoteMinimal:
+		if !d.needsQuote(cell) {
+			return dst, nil
+		}
+	}
+	quoted := make([]byte, 0, len(cell)+2)
+	quoted = append(quoted, '"')
+	for _, c := range cell {
+		if c == '"' {
+			quoted = append(quoted, '"')
+		}
+		quoted = append(quoted, c)
+	}
+	quoted = append(quoted, '"')
+	return append(dst[:start], quoted...), nil
+}
+
+func (d Delimited) needsQuote(cell []byte) bool {
+	if len(cell) == 0 {
+		return false
+	}
+	if string(cell) == `\.` {
+		return true
+	}
+	if bytes.ContainsRune(cell, d.delimiter()) || bytes.ContainsAny(cell, "\"\r\n") {
+		return true
+	}
+	r, _ := utf8.DecodeRune(cell)
+	return unicode.IsSpace(r)
+}
+
+// AppendDelimitedHeader appends the flattened header line of rowType.
+func (e *Engine) AppendDelimitedHeader(dst []byte, rowType reflect.Type, format Delimited) ([]byte, error) {
+	fp, err := e.flatPlanFor(rowType, format.Slices)
+	if err != nil {
+		return nil, err
+	}
+	return fp.appendHeader(dst, format)
+}
+
+// AppendDelimitedRow appends the line of a struct value or struct pointer; nil pointers append nothing.
+func (e *Engine) AppendDelimitedRow(dst []byte, value interface{}, format Delimited) ([]byte, error) {
+	rowType, ptr, err := rowPointer(value)
+	if err != nil || ptr == nil {
+		return dst, err
+	}
+	fp, err := e.flatPlanFor(rowType, format.Slices)
+	if err != nil {
+		return nil, err
+	}
+	return fp.appendRow(dst, ptr, format)
+}
+
+// MarshalDelimited appends the delimited encoding of a struct, struct slice or interface slice to dst.
+// Empty struct slices produce the header line only; rows of an interface slice must share one struct type.
+func (e *Engine) MarshalDelimited(dst []byte, value interface{}, format Delimited) ([]byte, error) {
+	if value == nil {
+		return dst, nil
+	}
+	rv := deref(reflect.ValueOf(value))
+	if !rv.IsValid() {
+		return dst, nil
+	}
+	var err error
+	switch rv.Kind() {
+	case reflect.Slice:
+		rowType := rv.Type().Elem()
+		for rowType.Kind() == reflect.Ptr {
+			rowType = rowType.Elem()
+		}
+		var fp *flatPlan
+		if rowType.Kind() == reflect.Struct {
+			t, err := e.planFor(rowType)
+			if err != nil {
+				return nil, err
+			}
+			if t, err = e.omitUnset(t, eachSliceRow(rv)); err != nil {
+				return nil, err
+			}
+			fp = e.flatPlanOf(t, format.Slices)
+			if dst, err = fp.appendHeader(dst, format); err != nil {
+				return nil, err
+			}
+		}
+		for i := 0; i < rv.Len(); i++ {
+			item := deref(rv.Index(i))
+			if !item.IsValid() || item.Kind() != reflect.Struct {
+				continue
+			}
+			if fp == nil {
+				rowType = item.Type()
+				if fp, err = e.flatPlanFor(rowType, format.Slices); err != nil {
+					return nil, err
+				}
+				if dst, err = fp.appendHeader(dst, format); err != nil {
+					return nil, err
+				}
+			} else if item.Type() != rowType {
+				return nil, fmt.Errorf("row %d type %s does not match header type %s", i, item.Type(), rowType)
+			}
+			_, ptr := addressableStruct(item)
+			if dst, err = fp.appendRow(dst, ptr, format); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	case reflect.Struct:
+		_, ptr := addressableStruct(rv)
+		t, err := e.planFor(rv.Type())
+		if err != nil {
+			return nil, err
+		}
+		if t, err = e.omitUnset(t, eachRow(ptr)); err != nil {
+			return nil, err
+		}
+		fp := e.flatPlanOf(t, format.Slices)
+		if dst, err = fp.appendHeader(dst, format); err != nil {
+			return nil, err
+		}
+		return fp.appendRow(dst, ptr, format)
+	default:
+		return nil, fmt.Errorf("unsupported root kind: %s", rv.Kind())
+	}
+}
@@ -1,7 +1,6 @@
 package marshal
 
 import (
-	stdjson "encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -19,6 +18,14 @@ type Engine struct {
 	compileName func(string) string
 	timeLayout  string
 	precision   int
+	// Columns projects the emitted headers, in order; dotted columns select child table columns.
+	Columns []string
+	// Presence controls how fields tracked by marker holders are written.
+	Presence PresenceMode
+	// Layout selects row-major or columnar JSON tables.
+	Layout Layout
+	// TypedHeaders annotates headers with their cell type, as in `id:int`.
+	TypedHeaders bool
 }
 
 func New(tagName, caseKey string, compileName func(string) string, timeLayout string, precision int) *Engine {
@@ -32,172 +39,144 @@ func New(tagName, caseKey string, compileName func(string) string, timeLayout st
 }
 
 func (e *Engine) Marshal(value interface{}) ([]byte, error) {
+	return e.MarshalTo(nil, value)
+}
+
+// MarshalTo appends the table encoding of value to dst.
+func (e *Engine) MarshalTo(dst []byte, value interface{}) ([]byte, error) {
 	if value == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
 	rv := reflect.ValueOf(value)
 	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Ptr {
 		if rv.IsNil() {
-			return []byte("null"), nil
+			return append(dst, "null"...), nil
 		}
 		rv = rv.Elem()
 	}
+	if e.Layout == ColumnarLayout {
+		return e.appendColumnar(dst, rv)
+	}
 
-	var rows []reflect.Value
 	switch rv.Kind() {
 	case reflect.Slice:
-		rows = make([]reflect.Value, 0, rv.Len())
-		for i := 0; i < rv.Len(); i++ {
-			item := deref(rv.Index(i))
-			if !item.IsValid() || item.Kind() != reflect.Struct {
-				continue
-			}
-			rows = append(rows, item)
+		rowType := rv.Type().Elem()
+		for rowType.Kind() == reflect.Ptr {
+			rowType = rowType.Elem()
 		}
+		if rowType.Kind() == reflect.Struct && rv.Type().Elem().Kind() != reflect.Interface {
+			return e.appendSliceTable(dst, rv, rowType)
+		}
+		return e.appendInterfaceSliceTable(dst, rv)
 	case reflect.Struct:
-		rows = []reflect.Value{rv}
+		row, ptr := addressableStruct(rv)
+		tp, err := e.rowPlanOf(row.Type(), eachRow(ptr))
+		if err != nil {
+			return nil, err
+		}
+		return tp.appendSingle(dst, ptr)
 	default:
 		return nil, fmt.Errorf("unsupported root kind: %s", rv.Kind())
 	}
+}
 
-	if len(rows) == 0 {
-		return stdjson.Marshal([]interface{}{})
-	}
-	p := plan.For(rows[0].Type(), e.tagName, e.caseKey, e.compileName)
-	table, err := e.marshalTable(p, rows)
+func (e *Engine) appendSliceTable(dst []byte, rv reflect.Value, rowType reflect.Type) ([]byte, error) {
+	tp, err := e.rowPlanOf(rowType, eachSliceRow(rv))
 	if err != nil {
 		return nil, err
 	}
-	return stdjson.Marshal(table)
-}
-
-func (e *Engine) marshalTable(p *plan.Type, rows []reflect.Value) ([]interface{}, error) {
-	out := make([]interface{}, 0, len(rows)+1)
-	headers := make([]interface{}, len(p.Headers))
-	for i := range p.Headers {
-		headers[i] = p.Headers[i]
-	}
-	out = append(out, headers)
-	for _, row := range rows {
-		rec, err := e.marshalRecord(p, row)
-		if err != nil {
+	elemType := rv.Type().Elem()
+	base := rv.UnsafePointer()
+	size := elemType.Size()
+	start := len(dst)
+	dst = append(dst, '[')
+	dst = append(dst, tp.header...)
+	rows := 0
+	for i := 0; i < rv.Len(); i++ {
+		rowPtr := unsafe.Add(base, uintptr(i)*size)
+		for t := elemType; t.Kind() == reflect.Ptr; t = t.Elem() {
+			rowPtr = *(*unsafe.Pointer)(rowPtr)
+			if rowPtr == nil {
+				break
+			}
+		}
+		if rowPtr == nil {
+			continue
+		}
+		dst = append(dst, ',')
+		if dst, err = tp.appendRow(dst, rowPtr); err != nil {
 			return nil, err
 		}
-		out = append(out, rec)
+		rows++
 	}
-	return out, nil
+	if rows == 0 {
+		return append(dst[:start], '[', ']'), nil
+	}
+	return append(dst, ']'), nil
 }
 
-func (e *Engine) marshalRecord(p *plan.Type, row reflect.Value) ([]interface{}, error) {
-	row, ptr := addressableStruct(row)
-	ret := make([]interface{}, len(p.Fields))
-	for i, f := range p.Fields {
-		fieldPtr := f.XField.Pointer(ptr)
-		switch f.Kind {
-		case plan.FieldScalar:
-			val, err := e.scalarValue(fieldPtr, f.Type)
-			if err != nil {
-				return nil, err
-			}
-			ret[i] = val
-		case plan.FieldStruct:
-			childVals, err := e.childStructTable(fieldPtr, f)
-			if err != nil {
-				return nil, err
-			}
-			ret[i] = childVals
-		case plan.FieldSliceStruct:
-			childVals, err := e.childSliceTable(fieldPtr, f)
-			if err != nil {
+func (e *Engine) appendInterfaceSliceTable(dst []byte, rv reflect.Value) ([]byte, error) {
+	var tp *tablePlan
+	var rowType reflect.Type
+	var err error
+	for i := 0; i < rv.Len(); i++ {
+		item := deref(rv.Index(i))
+		if !item.IsValid() || item.Kind() != reflect.Struct {
+			continue
+		}
+		row, ptr := addressableStruct(item)
+		if tp == nil {
+			if tp, err = e.rowPlan(row.Type()); err != nil {
 				return nil, err
 			}
-			ret[i] = childVals
-		}
-	}
-	return ret, nil
-}
-
-func (e *Engine) scalarValue(fieldPtr unsafe.Pointer, rType reflect.Type) (interface{}, error) {
-	if rType.Kind() == reflect.Ptr {
-		p := *(*unsafe.Pointer)(fieldPtr)
-		if p == nil {
-			return nil, nil
+			rowType = row.Type()
+			dst = append(dst, '[')
+			dst = append(dst, tp.header...)
+		} else if row.Type() != rowType {
+			return nil, fmt.Errorf("row %d type %s does not match header type %s", i, row.Type(), rowType)
 		}
-		return e.scalarValue(p, rType.Elem())
-	}
-	if rType == timeType {
-		tm := *(*time.Time)(fieldPtr)
-		if tm.IsZero() {
-			return nil, nil
+		dst = append(dst, ',')
+		if dst, err = tp.appendRow(dst, ptr); err != nil {
+			return nil, err
 		}
-		return tm.Format(e.timeLayout), nil
 	}
-	switch rType.Kind() {
-	case reflect.Float32:
-		value := *(*float32)(fieldPtr)
-		return e.formatFloat(float64(value), 32)
-	case reflect.Float64:
-		value := *(*float64)(fieldPtr)
-		return e.formatFloat(value, 64)
+	if tp == nil {
+		return append(dst, '[', ']'), nil
 	}
-	return reflect.NewAt(rType, fieldPtr).Elem().Interface(), nil
+	return append(dst, ']'), nil
 }
 
-func (e *Engine) formatFloat(value float64, bitSize int) (interface{}, error) {
-	if e.precision < 0 {
-		if bitSize == 32 {
-			return float32(value), nil
-		}
-		return value, nil
-	}
-	formatted := strconv.FormatFloat(value, 'f', e.precision, bitSize)
-	parsed, err := strconv.ParseFloat(formatted, bitSize)
+// rowPlan returns the compiled table plan of rowType with the column projection applied.
+func (e *Engine) rowPlan(rowType reflect.Type) (*tablePlan, error) {
+	t, err := e.planFor(rowType)
 	if err != nil {
 		return nil, err
 	}
-	if bitSize == 32 {
-		return float32(parsed), nil
-	}
-	return parsed, nil
+	return e.tablePlanFor(t), nil
 }
 
-func (e *Engine) childStructTable(fieldPtr unsafe.Pointer, f *plan.Field) (interface{}, error) {
-	vType := f.Type
-	if vType.Kind() == reflect.Ptr {
-		next := *(*unsafe.Pointer)(fieldPtr)
-		if next == nil {
-			return nil, nil
-		}
-		vType = vType.Elem()
-		val := reflect.NewAt(vType, next).Elem()
-		table, err := e.marshalTable(f.Child, []reflect.Value{val})
-		if err != nil {
-			return nil, err
-		}
-		return table, nil
-	}
-	val := reflect.NewAt(vType, fieldPtr).Elem()
-	table, err := e.marshalTable(f.Child, []reflect.Value{val})
+// rowPlanOf returns the row plan of rowType, dropping columns unset in every row in PresenceOmit mode.
+func (e *Engine) rowPlanOf(rowType reflect.Type, each func(visit func(rowPtr unsafe.Pointer))) (*tablePlan, error) {
+	t, err := e.planFor(rowType)
 	if err != nil {
 		return nil, err
 	}
-	return table, nil
+	if t, err = e.omitUnset(t, each); err != nil {
+		return nil, err
+	}
+	return e.tablePlanFor(t), nil
 }
 
-func (e *Engine) childSliceTable(fieldPtr unsafe.Pointer, f *plan.Field) (interface{}, error) {
-	rv := reflect.NewAt(f.Type, fieldPtr).Elem()
-	if rv.IsNil() || rv.Len() == 0 {
-		return nil, nil
-	}
-	rows := make([]reflect.Value, 0, rv.Len())
-	for i := 0; i < rv.Len(); i++ {
-		rows = append(rows, deref(rv.Index(i)))
-	}
-	table, err := e.marshalTable(f.Child, rows)
-	if err != nil {
-		return nil, err
+func (e *Engine) planFor(rowType reflect.Type) (*plan.Type, error) {
+	return plan.Project(plan.For(rowType, e.tagName, e.caseKey, e.compileName), e.Columns)
+}
+
+func (e *Engine) formatFloat(value float64, bitSize int) (float64, error) {
+	if e.precision < 0 {
+		return value, nil
 	}
-	return table, nil
+	formatted := strconv.FormatFloat(value, 'f', e.precision, bitSize)
+	return strconv.ParseFloat(formatted, bitSize)
 }
 
 func deref(v reflect.Value) reflect.Value {
@@ -0,0 +1,370 @@
+package marshal
+
+import (
+	"encoding"
+	stdjson "encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/viant/structology/encoding/jsontab/internal/lru"
+	"github.com/viant/structology/encoding/jsontab/internal/plan"
+)
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// cellOp appends one encoded cell read from the field at ptr.
+type cellOp func(dst []byte, ptr unsafe.Pointer) ([]byte, error)
+
+// tablePlan is a compiled append-only encoder for one plan.Type.
+type tablePlan struct {
+	header []byte
+	fields []*plan.Field
+	ops    []cellOp
+	text   []cellOp
+	// checks hold per-field marker checks; nil unless a presence mode applies.
+	checks []*presenceCheck
+}
+
+type tablePlanKey struct {
+	t          *plan.Type
+	timeLayout string
+	precision  int
+	presence   PresenceMode
+	typed      bool
+}
+
+// tablePlans is bounded since projected plan types are keyed by caller-supplied columns.
+var tablePlans = lru.New[tablePlanKey, *tablePlan](2048)
+
+func (e *Engine) tablePlanFor(t *plan.Type) *tablePlan {
+	key := e.tablePlanKey(t)
+	if v, ok := tablePlans.Get(key); ok {
+		return v
+	}
+	compiled := e.compileTable(t)
+	tablePlans.Set(key, compiled)
+	return compiled
+}
+
+func (e *Engine) tablePlanKey(t *plan.Type) tablePlanKey {
+	return tablePlanKey{t: t, timeLayout: e.timeLayout, precision: e.precision, presence: e.Presence, typed: e.TypedHeaders}
+}
+
+// headerName returns the emitted header of field i of t.
+func (e *Engine) headerName(t *plan.Type, i int) string {
+	if e.TypedHeaders {
+		return t.Headers[i] + ":" + t.Fields[i].TypeName()
+	}
+	return t.Headers[i]
+}
+
+func (e *Engine) compileTable(t *plan.Type) *tablePlan {
+	ret := &tablePlan{fields: t.Fields, ops: make([]cellOp, len(t.Fields)), text: make([]cellOp, len(t.Fields))}
+	ret.header = append(ret.header, '[')
+	for i := range t.Headers {
+		if i > 0 {
+			ret.header = append(ret.header, ',')
+		}
+		ret.header = appendString(ret.header, e.headerName(t, i))
+	}
+	ret.header = append(ret.header, ']')
+	for i, f := range t.Fields {
+		switch f.Kind {
+		case plan.FieldStruct:
+			ret.ops[i] = e.compileChildStruct(f)
+		case plan.FieldSliceStruct:
+			ret.ops[i] = e.compileChildSlice(f)
+		default:
+			ret.ops[i] = e.compileCell(f)
+		}
+		ret.text[i] = e.compileText(f, ret.ops[i])
+		if check := e.presenceCheck(t, f); check != nil {
+			if ret.checks == nil {
+				ret.checks = make([]*presenceCheck, len(t.Fields))
+			}
+			ret.checks[i] = check
+		}
+	}
+	return ret
+}
+
+func (p *tablePlan) appendRow(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+	var err error
+	dst = append(dst, '[')
+	for i, op := range p.ops {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		if p.checks != nil && p.checks[i] != nil && p.checks[i].unset(ptr) {
+			dst = append(dst, "null"...)
+			continue
+		}
+		if dst, err = op(dst, p.fields[i].XField.Pointer(ptr)); err != nil {
+			return nil, err
+		}
+	}
+	return append(dst, ']'), nil
+}
+
+// appendSingle appends a one-row table.
+func (p *tablePlan) appendSingle(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+	dst = append(dst, '[')
+	dst = append(dst, p.header...)
+	dst = append(dst, ',')
+	dst, err := p.appendRow(dst, ptr)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, ']'), nil
+}
+
+func (e *Engine) compileChildStruct(f *plan.Field) cellOp {
+	child := e.tablePlanFor(f.Child)
+	ptrDepth := 0
+	for t := f.Type; t.Kind() == reflect.Ptr; t = t.Elem() {
+		ptrDepth++
+	}
+	return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+		for i := 0; i < ptrDepth; i++ {
+			if ptr = *(*unsafe.Pointer)(ptr); ptr == nil {
+				return append(dst, "null"...), nil
+			}
+		}
+		return child.appendSingle(dst, ptr)
+	}
+}
+
+func (e *Engine) compileChildSlice(f *plan.Field) cellOp {
+	child := e.tablePlanFor(f.Child)
+	elemType := f.Type.Elem()
+	size := elemType.Size()
+	ptrDepth := 0
+	for t := elemType; t.Kind() == reflect.Ptr; t = t.Elem() {
+		ptrDepth++
+	}
+	return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+		header := (*sliceHeader)(ptr)
+		if header.Data == nil || header.Len == 0 {
+			return append(dst, "null"...), nil
+		}
+		var err error
+		dst = append(dst, '[')
+		dst = append(dst, child.header...)
+		for i := 0; i < header.Len; i++ {
+			dst = append(dst, ',')
+			rowPtr := unsafe.Add(header.Data, uintptr(i)*size)
+			for j := 0; j < ptrDepth && rowPtr != nil; j++ {
+				rowPtr = *(*unsafe.Pointer)(rowPtr)
+			}
+			if rowPtr == nil {
+				dst = append(dst, "null"...)
+				continue
+			}
+			if dst, err = child.appendRow(dst, rowPtr); err != nil {
+				return nil, err
+			}
+		}
+		return append(dst, ']'), nil
+	}
+}
+
+type sliceHeader struct {
+	Data unsafe.Pointer
+	Len  int
+	Cap  int
+}
+
+func (e *Engine) compileScalar(rType reflect.Type) cellOp {
+	if rType.Kind() == reflect.Ptr {
+		inner := e.compileScalar(rType.Elem())
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			next := *(*unsafe.Pointer)(ptr)
+			if next == nil {
+				return append(dst, "null"...), nil
+			}
+			return inner(dst, next)
+		}
+	}
+	if rType == timeType {
+		layout := e.timeLayout
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			tm := *(*time.Time)(ptr)
+			if tm.IsZero() {
+				return append(dst, "null"...), nil
+			}
+			return appendString(dst, tm.Format(layout)), nil
+		}
+	}
+	switch rType.Kind() {
+	case reflect.String:
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			return appendString(dst, *(*string)(ptr)), nil
+		}
+	case reflect.Bool:
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			return strconv.AppendBool(dst, *(*bool)(ptr)), nil
+		}
+	case reflect.Int:
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			return strconv.AppendInt(dst, int64(*(*int)(ptr)), 10), nil
+		}
+	case reflect.Int8:
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			return strconv.AppendInt(dst, int64(*(*int8)(ptr)), 10), nil
+		}
+	case reflect.Int16:
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			return strconv.AppendInt(dst, int64(*(*int16)(ptr)), 10), nil
+		}
+	case reflect.Int32:
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			return strconv.AppendInt(dst, int64(*(*int32)(ptr)), 10), nil
+		}
+	case reflect.Int64:
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			return strconv.AppendInt(dst, *(*int64)(ptr), 10), nil
+		}
+	case reflect.Uint:
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			return strconv.AppendUint(dst, uint64(*(*uint)(ptr)), 10), nil
+		}
+	case reflect.Uint8:
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			return strconv.AppendUint(dst, uint64(*(*uint8)(ptr)), 10), nil
+		}
+	case reflect.Uint16:
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			return strconv.AppendUint(dst, uint64(*(*uint16)(ptr)), 10), nil
+		}
+	case reflect.Uint32:
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			return strconv.AppendUint(dst, uint64(*(*uint32)(ptr)), 10), nil
+		}
+	case reflect.Uint64:
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			return strconv.AppendUint(dst, *(*uint64)(ptr), 10), nil
+		}
+	case reflect.Uintptr:
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			return strconv.AppendUint(dst, uint64(*(*uintptr)(ptr)), 10), nil
+		}
+	case reflect.Float32:
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			value, err := e.formatFloat(float64(*(*float32)(ptr)), 32)
+			if err != nil {
+				return nil, err
+			}
+			return appendFloat(dst, value, 32)
+		}
+	case reflect.Float64:
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			value, err := e.formatFloat(*(*float64)(ptr), 64)
+			if err != nil {
+				return nil, err
+			}
+			return appendFloat(dst, value, 64)
+		}
+	}
+	return fallbackOp(rType)
+}
+
+// compileCell returns the JSON op of a scalar field according to its cell encoding.
+func (e *Engine) compileCell(f *plan.Field) cellOp {
+	if f.Cell == plan.CellValue {
+		return e.compileScalar(f.Type)
+	}
+	return fallbackOp(f.Type)
+}
+
+// fallbackOp encodes values without a dedicated op (maps, slices, interfaces, marshalers) through encoding/json.
+// The value is passed by pointer so that pointer-receiver marshalers are honoured.
+func fallbackOp(rType reflect.Type) cellOp {
+	return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+		data, err := stdjson.Marshal(reflect.NewAt(rType, ptr).Interface())
+		if err != nil {
+			return nil, err
+		}
+		return append(dst, data...), nil
+	}
+}
+
+// appendFloat formats floats the way encoding/json does.
+func appendFloat(dst []byte, value float64, bitSize int) ([]byte, error) {
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return nil, &stdjson.UnsupportedValueError{Value: reflect.ValueOf(value), Str: strconv.FormatFloat(value, 'g', -1, bitSize)}
+	}
+	format := byte('f')
+	if abs := math.Abs(value); abs != 0 {
+		if bitSize == 64 && (abs < 1e-6 || abs >= 1e21) || bitSize == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	dst = strconv.AppendFloat(dst, value, format, -1, bitSize)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		n := len(dst)
+		if n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+	return dst, nil
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendString quotes s the way encoding/json does, including HTML-safe escaping.
+func appendString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '\\', '"':
+				dst = append(dst, '\\', b)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
@@ -0,0 +1,96 @@
+package marshal
+
+import (
+	"reflect"
+	"unsafe"
+
+	"github.com/viant/structology/encoding/jsontab/internal/plan"
+	"github.com/viant/xunsafe"
+)
+
+// PresenceMode controls how fields tracked by `setMarker` holders are written.
+type PresenceMode int
+
+const (
+	// PresenceIgnore writes field values regardless of markers.
+	PresenceIgnore PresenceMode = iota
+	// PresenceNull writes null (an empty delimited cell) for fields whose marker is unset.
+	PresenceNull
+	// PresenceOmit behaves like PresenceNull and also drops top-level columns unset in every row.
+	PresenceOmit
+)
+
+// presenceCheck reads the marker of one field; rows without an allocated holder count as set.
+type presenceCheck struct {
+	presence *plan.PresencePlan
+	flag     *xunsafe.Field
+}
+
+func (c *presenceCheck) unset(rowPtr unsafe.Pointer) bool {
+	holder := plan.PresenceHolder(rowPtr, c.presence)
+	return holder != nil && !c.flag.Bool(holder)
+}
+
+func (e *Engine) presenceCheck(t *plan.Type, f *plan.Field) *presenceCheck {
+	if e.Presence == PresenceIgnore || t.Presence == nil {
+		return nil
+	}
+	flag := t.Presence.Flags[f.StructName]
+	if flag == nil {
+		return nil
+	}
+	return &presenceCheck{presence: t.Presence, flag: flag}
+}
+
+// omitUnset projects t onto the columns set in at least one of the rows visited by each.
+func (e *Engine) omitUnset(t *plan.Type, each func(visit func(rowPtr unsafe.Pointer))) (*plan.Type, error) {
+	if e.Presence != PresenceOmit || t.Presence == nil {
+		return t, nil
+	}
+	used := make([]bool, len(t.Fields))
+	rows := 0
+	each(func(rowPtr unsafe.Pointer) {
+		rows++
+		holder := plan.PresenceHolder(rowPtr, t.Presence)
+		for i, f := range t.Fields {
+			if used[i] {
+				continue
+			}
+			flag := t.Presence.Flags[f.StructName]
+			used[i] = holder == nil || flag == nil || flag.Bool(holder)
+		}
+	})
+	var keep []string
+	for i, header := range t.Headers {
+		if used[i] {
+			keep = append(keep, header)
+		}
+	}
+	if rows == 0 || len(keep) == 0 || len(keep) == len(t.Headers) {
+		return t, nil
+	}
+	return plan.Project(t, keep)
+}
+
+// eachSliceRow visits the non-nil struct rows of a struct or struct pointer slice.
+func eachSliceRow(rv reflect.Value) func(visit func(rowPtr unsafe.Pointer)) {
+	return func(visit func(rowPtr unsafe.Pointer)) {
+		elemType := rv.Type().Elem()
+		base := rv.UnsafePointer()
+		size := elemType.Size()
+		for i := 0; i < rv.Len(); i++ {
+			rowPtr := unsafe.Add(base, uintptr(i)*size)
+			for t := elemType; t.Kind() == reflect.Ptr && rowPtr != nil; t = t.Elem() {
+				rowPtr = *(*unsafe.Pointer)(rowPtr)
+			}
+			if rowPtr != nil {
+				visit(rowPtr)
+			}
+		}
+	}
+}
+
+// eachRow visits a single row.
+func eachRow(rowPtr unsafe.Pointer) func(visit func(rowPtr unsafe.Pointer)) {
+	return func(visit func(rowPtr unsafe.Pointer)) { visit(rowPtr) }
+}
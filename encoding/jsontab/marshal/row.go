@@ -0,0 +1,172 @@
+package marshal
+
+import (
+	"encoding"
+	stdjson "encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+	"unsafe"
+
+	"github.com/viant/structology/encoding/jsontab/internal/plan"
+)
+
+// AppendHeader appends the JSON header row of rowType.
+func (e *Engine) AppendHeader(dst []byte, rowType reflect.Type) ([]byte, error) {
+	tp, err := e.rowPlan(rowType)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, tp.header...), nil
+}
+
+// AppendRow appends the JSON record of a struct value or struct pointer.
+func (e *Engine) AppendRow(dst []byte, value interface{}) ([]byte, error) {
+	rowType, ptr, err := rowPointer(value)
+	if err != nil {
+		return nil, err
+	}
+	if ptr == nil {
+		return append(dst, "null"...), nil
+	}
+	tp, err := e.rowPlan(rowType)
+	if err != nil {
+		return nil, err
+	}
+	return tp.appendRow(dst, ptr)
+}
+
+func rowPointer(value interface{}) (reflect.Type, unsafe.Pointer, error) {
+	if value == nil {
+		return nil, nil, fmt.Errorf("nil row")
+	}
+	rv := reflect.ValueOf(value)
+	rowType := rv.Type()
+	for rowType.Kind() == reflect.Ptr {
+		rowType = rowType.Elem()
+	}
+	if rowType.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("unsupported row kind: %s", rowType.Kind())
+	}
+	rv = deref(rv)
+	if !rv.IsValid() {
+		return rowType, nil, nil
+	}
+	_, ptr := addressableStruct(rv)
+	return rowType, ptr, nil
+}
+
+// compileText compiles the delimited-text encoding of a field; it reuses the JSON op where the forms agree.
+func (e *Engine) compileText(f *plan.Field, jsonOp cellOp) cellOp {
+	switch {
+	case f.Kind == plan.FieldScalar && f.Type.Kind() == reflect.Interface:
+		return interfaceText(jsonOp)
+	case f.Kind != plan.FieldScalar, f.Cell == plan.CellJSON:
+		return nullAsEmpty(jsonOp)
+	case f.Cell == plan.CellText:
+		if op := compileMarshalText(f.Type); op != nil {
+			return op
+		}
+		return nullAsEmpty(jsonOp)
+	}
+	return e.compileTextScalar(f.Type, jsonOp)
+}
+
+// compileMarshalText writes the MarshalText form of a value, or returns nil when rType is not a TextMarshaler.
+// Nil pointers write an empty cell.
+func compileMarshalText(rType reflect.Type) cellOp {
+	if rType.Kind() == reflect.Ptr {
+		inner := compileMarshalText(rType.Elem())
+		if inner == nil {
+			return nil
+		}
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			next := *(*unsafe.Pointer)(ptr)
+			if next == nil {
+				return dst, nil
+			}
+			return inner(dst, next)
+		}
+	}
+	if !reflect.PointerTo(rType).Implements(textMarshalerType) {
+		return nil
+	}
+	return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+		text, err := reflect.NewAt(rType, ptr).Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return append(dst, text...), nil
+	}
+}
+
+func (e *Engine) compileTextScalar(rType reflect.Type, jsonOp cellOp) cellOp {
+	if rType.Kind() == reflect.Ptr {
+		inner := e.compileTextScalar(rType.Elem(), e.compileScalar(rType.Elem()))
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			next := *(*unsafe.Pointer)(ptr)
+			if next == nil {
+				return dst, nil
+			}
+			return inner(dst, next)
+		}
+	}
+	if rType == timeType {
+		layout := e.timeLayout
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			tm := *(*time.Time)(ptr)
+			if tm.IsZero() {
+				return dst, nil
+			}
+			return tm.AppendFormat(dst, layout), nil
+		}
+	}
+	if rType.Kind() == reflect.String {
+		return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+			return append(dst, *(*string)(ptr)...), nil
+		}
+	}
+	return nullAsEmpty(jsonOp)
+}
+
+// interfaceText writes interface values like nullAsEmpty, but keeps the JSON quotes of strings whose text
+// would read back as another JSON value, so "123" is not decoded as a number.
+func interfaceText(op cellOp) cellOp {
+	return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+		start := len(dst)
+		dst, err := op(dst, ptr)
+		if err != nil {
+			return nil, err
+		}
+		if len(dst)-start == 4 && string(dst[start:]) == "null" {
+			return dst[:start], nil
+		}
+		if len(dst)-start >= 2 && dst[start] == '"' {
+			if text, err := strconv.Unquote(string(dst[start:])); err == nil && text != "" && !stdjson.Valid([]byte(text)) {
+				return append(dst[:start], text...), nil
+			}
+		}
+		return dst, nil
+	}
+}
+
+func nullAsEmpty(op cellOp) cellOp {
+	return func(dst []byte, ptr unsafe.Pointer) ([]byte, error) {
+		start := len(dst)
+		dst, err := op(dst, ptr)
+		if err != nil {
+			return nil, err
+		}
+		if len(dst)-start == 4 && string(dst[start:]) == "null" {
+			return dst[:start], nil
+		}
+		if len(dst)-start >= 2 && dst[start] == '"' {
+			// JSON-only types encoded as strings (for example base64 bytes) are written unquoted.
+			if text, err := strconv.Unquote(string(dst[start:])); err == nil {
+				return append(dst[:start], text...), nil
+			}
+		}
+		return dst, nil
+	}
+}
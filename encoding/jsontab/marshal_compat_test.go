@@ -0,0 +1,103 @@
+package jsontab
+
+import (
+	stdjson "encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type compatLevel int
+
+func (l compatLevel) MarshalText() ([]byte, error) {
+	return []byte("L" + string(rune('0'+int(l)))), nil
+}
+
+type compatChild struct {
+	Code  string `csvName:"code"`
+	Score float32
+}
+
+type compatRow struct {
+	Name    string
+	Tiny    float64
+	Huge    float64
+	Ratio   float32
+	Count   *int
+	At      time.Time
+	Tags    []string
+	Attrs   map[string]int
+	Level   compatLevel
+	Bytes   []byte
+	Child   *compatChild
+	Items   []*compatChild
+	Uint    uint16
+	Iface   interface{}
+	private int
+}
+
+func TestMarshal_MatchesStdlibCellEncoding(t *testing.T) {
+	count := 3
+	rows := []compatRow{
+		{
+			Name:  "<a href=\"x\">&\u2028\t\x01",
+			Tiny:  1e-7,
+			Huge:  1e21,
+			Ratio: 0.1,
+			Count: &count,
+			At:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			Tags:  []string{"x"},
+			Attrs: map[string]int{"b": 2, "a": 1},
+			Level: 2,
+			Bytes: []byte("hi"),
+			Child: &compatChild{Code: "c", Score: 1.5},
+			Items: []*compatChild{{Code: "i1"}, {Code: "i2", Score: 2e-9}},
+			Uint:  65535,
+			Iface: map[string]interface{}{"k": []int{1}},
+		},
+		{Name: "bad\xffutf8"},
+	}
+	actual, err := Marshal(rows)
+	require.NoError(t, err)
+
+	child := func(c *compatChild) interface{} { return []interface{}{c.Code, c.Score} }
+	expected, err := stdjson.Marshal([]interface{}{
+		[]interface{}{"Name", "Tiny", "Huge", "Ratio", "Count", "At", "Tags", "Attrs", "Level", "Bytes", "Child", "Items", "Uint", "Iface"},
+		[]interface{}{rows[0].Name, rows[0].Tiny, rows[0].Huge, rows[0].Ratio, count, "2026-01-02T03:04:05Z", rows[0].Tags, rows[0].Attrs, rows[0].Level, rows[0].Bytes,
+			[]interface{}{[]interface{}{"code", "Score"}, child(rows[0].Child)},
+			[]interface{}{[]interface{}{"code", "Score"}, child(rows[0].Items[0]), child(rows[0].Items[1])},
+			rows[0].Uint, rows[0].Iface},
+		[]interface{}{rows[1].Name, 0, 0, 0, nil, nil, nil, nil, rows[1].Level, nil, nil, nil, 0, nil},
+	})
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(actual))
+}
+
+func TestMarshal_EmptyAndNilRows(t *testing.T) {
+	type item struct {
+		ID int `csvName:"id"`
+	}
+	data, err := Marshal([]*item{nil, nil})
+	require.NoError(t, err)
+	require.Equal(t, `[]`, string(data))
+
+	data, err = Marshal([]*item{nil, {ID: 1}})
+	require.NoError(t, err)
+	require.Equal(t, `[["id"],[1]]`, string(data))
+}
+
+func TestMarshal_InterfaceSliceMixedRowTypes(t *testing.T) {
+	type Big struct {
+		A, B, C, D string
+	}
+	type Two struct {
+		P, L int
+	}
+	data, err := Marshal([]interface{}{nil, Big{A: "a"}, &Big{B: "b"}})
+	require.NoError(t, err)
+	require.Equal(t, `[["A","B","C","D"],["a","","",""],["","b","",""]]`, string(data))
+
+	_, err = Marshal([]interface{}{Big{}, Two{P: 16, L: 10}})
+	require.ErrorContains(t, err, "does not match header type")
+}
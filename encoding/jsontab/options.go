@@ -71,6 +71,49 @@ func WithFloatPrecision(precision int) Option {
 	})
 }
 
+// WithOutputFormat selects the table form written by encoders.
+func WithOutputFormat(format OutputFormat) Option {
+	return optionFn(func(o *Options) { o.OutputFormat = format })
+}
+
+// WithDelimiter sets the delimited cell separator for both output and input; CSV defaults to ',' and TSV to a tab.
+func WithDelimiter(delimiter rune) Option {
+	return optionFn(func(o *Options) { o.Delimiter = delimiter })
+}
+
+// WithQuoting sets the quoting of delimited output cells.
+func WithQuoting(quoting Quoting) Option {
+	return optionFn(func(o *Options) { o.Quoting = quoting })
+}
+
+// WithSlicePolicy controls struct slice children in delimited output.
+func WithSlicePolicy(policy SlicePolicy) Option {
+	return optionFn(func(o *Options) { o.SlicePolicy = policy })
+}
+
+// WithColumns restricts marshal output and unmarshal input to the selected headers, emitted in the given order.
+// Dotted columns such as `children.id` select columns of child tables.
+func WithColumns(columns ...string) Option {
+	return optionFn(func(o *Options) { o.Columns = columns })
+}
+
+// WithPresence makes marshal honour `setMarker` bits, writing unset fields as null or omitting columns unset in every row;
+// unmarshal then leaves markers of null scalar cells unset.
+func WithPresence(mode PresenceMode) Option {
+	return optionFn(func(o *Options) { o.Presence = mode })
+}
+
+// WithLayout selects the JSON table layout written by Marshal; streaming encoders always write rows.
+func WithLayout(layout Layout) Option {
+	return optionFn(func(o *Options) { o.Layout = layout })
+}
+
+// WithTypedHeaders annotates marshalled headers with value types (`id:int`, `at:time`, `children:table`).
+// Unmarshal accepts annotated headers either way and, with ErrorOnMalformed, rejects ones that contradict the target field.
+func WithTypedHeaders(enabled bool) Option {
+	return optionFn(func(o *Options) { o.TypedHeaders = enabled })
+}
+
 func defaultOptions() Options {
 	return Options{
 		Mode:                ModeCompat,
@@ -104,6 +147,12 @@ func resolveOptions(ctx context.Context, opts []Option) Options {
 	if result.TimeLayout == "" {
 		result.TimeLayout = time.RFC3339
 	}
+	if result.Delimiter == 0 {
+		result.Delimiter = ','
+		if result.OutputFormat == TSV {
+			result.Delimiter = '\t'
+		}
+	}
 	if !result.setPrecision {
 		result.FloatPrecision = -1
 	}
@@ -0,0 +1,99 @@
+package jsontab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type presenceAddrHas struct {
+	City bool
+	Zip  bool
+}
+
+type presenceAddr struct {
+	City string           `csvName:"city"`
+	Zip  string           `csvName:"zip"`
+	Has  *presenceAddrHas `setMarker:"true"`
+}
+
+type presenceRecHas struct {
+	ID      bool
+	Name    bool
+	Score   bool
+	Address bool
+}
+
+type presenceRec struct {
+	ID      int             `csvName:"id"`
+	Name    string          `csvName:"name"`
+	Score   int             `csvName:"score"`
+	Address *presenceAddr   `csvName:"address"`
+	Has     *presenceRecHas `setMarker:"true"`
+}
+
+func presenceRows() []presenceRec {
+	return []presenceRec{
+		{ID: 1, Name: "", Score: 0, Has: &presenceRecHas{ID: true, Name: true}},
+		{ID: 2, Score: 5, Address: &presenceAddr{City: "Oslo", Has: &presenceAddrHas{City: true}}, Has: &presenceRecHas{ID: true, Address: true}},
+		{ID: 3, Name: "legacy"},
+	}
+}
+
+func TestMarshal_PresenceNull(t *testing.T) {
+	rows := presenceRows()
+	data, err := Marshal(rows)
+	require.NoError(t, err)
+	require.Equal(t, `[["id","name","score","address"],[1,"",0,null],[2,"",5,[["city","zip"],["Oslo",""]]],[3,"legacy",0,null]]`, string(data))
+
+	data, err = Marshal(rows, WithPresence(PresenceNull))
+	require.NoError(t, err)
+	require.Equal(t, `[["id","name","score","address"],[1,"",null,null],[2,null,null,[["city","zip"],["Oslo",null]]],[3,"legacy",0,null]]`, string(data))
+
+	var out []presenceRec
+	require.NoError(t, Unmarshal(data, &out))
+	require.True(t, out[1].Has.Name)
+
+	out = nil
+	require.NoError(t, Unmarshal(data, &out, WithPresence(PresenceNull)))
+	require.False(t, out[1].Has.Name)
+	require.True(t, out[1].Has.Address)
+	require.True(t, out[1].Address.Has.City)
+	require.False(t, out[1].Address.Has.Zip)
+
+	data, err = Marshal(rows, WithPresence(PresenceNull), WithOutputFormat(CSV))
+	require.NoError(t, err)
+	require.Equal(t, "id,name,score,address.city,address.zip\n1,,,,\n2,,,Oslo,\n3,legacy,0,,\n", string(data))
+
+	// the second input holds a quoted cell, so it is read through the full CSV reader
+	for _, csvData := range [][]byte{data, []byte("id,name,score,address.city,address.zip\n1,,,,\n2,,,\"Oslo\",\n3,legacy,0,,\n")} {
+		out = nil
+		require.NoError(t, Unmarshal(csvData, &out))
+		require.True(t, out[1].Has.Name)
+		require.True(t, out[1].Has.Score)
+
+		out = nil
+		require.NoError(t, Unmarshal(csvData, &out, WithPresence(PresenceNull)))
+		require.True(t, out[1].Has.ID)
+		require.False(t, out[1].Has.Name)
+		require.False(t, out[1].Has.Score)
+		require.True(t, out[1].Address.Has.City)
+		require.False(t, out[1].Address.Has.Zip)
+		require.True(t, out[2].Has.Score)
+	}
+}
+
+func TestMarshal_PresenceOmit(t *testing.T) {
+	rows := presenceRows()[:2]
+	data, err := Marshal(rows, WithPresence(PresenceOmit))
+	require.NoError(t, err)
+	require.Equal(t, `[["id","name","address"],[1,"",null],[2,null,[["city","zip"],["Oslo",null]]]]`, string(data))
+
+	data, err = Marshal(rows[0], WithPresence(PresenceOmit), WithOutputFormat(CSV))
+	require.NoError(t, err)
+	require.Equal(t, "id,name\n1,\n", string(data))
+
+	data, err = Marshal(presenceRows(), WithPresence(PresenceOmit))
+	require.NoError(t, err)
+	require.Contains(t, string(data), `["id","name","score","address"]`)
+}
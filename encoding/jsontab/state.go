@@ -0,0 +1,121 @@
+package jsontab
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/viant/structology"
+	"github.com/viant/structology/encoding/jsontab/internal/plan"
+)
+
+var interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// RowStateType synthesizes a row struct for headers known only at runtime and wraps it in a StateType.
+// Typed headers (`id:int`, `at:time`) choose the field type; untyped, json and table columns hold interface{}.
+// Each field is tagged with the configured tag name, and a `setMarker` holder built with
+// structology.GenMarkerFields records which cells were present.
+// Selectors resolve both the generated field name and the header name.
+func RowStateType(headers []string, opts ...Option) *structology.StateType {
+	cfg := resolveOptions(context.Background(), opts)
+	tagName := normalizeTagName(cfg.TagName)
+	used := map[string]bool{}
+	fields := make([]reflect.StructField, 0, len(headers)+1)
+	for i, header := range headers {
+		name, typeName := plan.SplitTypedHeader(header)
+		fields = append(fields, reflect.StructField{
+			Name: uniqueFieldName(used, fieldNameOf(name, i)),
+			Type: headerFieldType(typeName),
+			Tag:  reflect.StructTag(tagName + ":" + strconv.Quote(name)),
+		})
+	}
+	markerType := reflect.PointerTo(reflect.StructOf(structology.GenMarkerFields(reflect.StructOf(fields))))
+	fields = append(fields, reflect.StructField{
+		Name: uniqueFieldName(used, "Has"),
+		Type: markerType,
+		Tag:  reflect.StructTag(structology.SetMarkerTag + `:"true" ` + tagName + `:"-"`),
+	})
+	return structology.NewStateType(reflect.StructOf(fields), structology.WithCustomizedNames(func(name string, tag reflect.StructTag) []string {
+		if header := tag.Get(tagName); header != "" && header != "-" && header != name {
+			return []string{name, header}
+		}
+		return []string{name}
+	}))
+}
+
+// UnmarshalStates decodes a table whose columns are only known at runtime.
+// The row type is synthesized from the input headers with RowStateType.
+func UnmarshalStates(data []byte, opts ...Option) (*structology.StateType, []*structology.State, error) {
+	return UnmarshalStatesContext(context.Background(), data, opts...)
+}
+
+// UnmarshalStatesContext is UnmarshalStates with a context.
+func UnmarshalStatesContext(ctx context.Context, data []byte, opts ...Option) (*structology.StateType, []*structology.State, error) {
+	u := newUnmarshalEngine(resolveOptions(ctx, opts))
+	headers, err := u.Headers(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	stateType := RowStateType(headers, opts...)
+	rows := reflect.New(reflect.SliceOf(reflect.PointerTo(stateType.Type())))
+	if err = u.Unmarshal(data, rows.Interface()); err != nil {
+		return nil, nil, err
+	}
+	states := make([]*structology.State, rows.Elem().Len())
+	for i := range states {
+		states[i] = stateType.WithValue(rows.Elem().Index(i).Interface())
+	}
+	return stateType, states, nil
+}
+
+func headerFieldType(typeName string) reflect.Type {
+	switch typeName {
+	case plan.TypeString:
+		return reflect.TypeOf("")
+	case plan.TypeInt:
+		return reflect.TypeOf(int64(0))
+	case plan.TypeFloat:
+		return reflect.TypeOf(float64(0))
+	case plan.TypeBool:
+		return reflect.TypeOf(false)
+	case plan.TypeTime:
+		return reflect.TypeOf(time.Time{})
+	}
+	return interfaceType
+}
+
+// fieldNameOf converts a header into an exported Go identifier, e.g. `user_id` to `UserId`.
+func fieldNameOf(header string, index int) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range header {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if sb.Len() == 0 && unicode.IsDigit(r) {
+			sb.WriteByte('F')
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	if sb.Len() == 0 || !unicode.IsUpper([]rune(sb.String())[0]) {
+		return "Column" + strconv.Itoa(index+1)
+	}
+	return sb.String()
+}
+
+func uniqueFieldName(used map[string]bool, name string) string {
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	used[candidate] = true
+	return candidate
+}
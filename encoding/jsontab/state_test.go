@@ -0,0 +1,71 @@
+package jsontab
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalStates_TypedHeaders(t *testing.T) {
+	data := []byte(`[["user_id:int","name","score:float","at:time","tags"],` +
+		`[1,"a",1.5,"2024-05-01T10:00:00Z",["x"]],[2,null,null,null,null]]`)
+	stateType, states, err := UnmarshalStates(data)
+	require.NoError(t, err)
+	require.True(t, stateType.HasMarker())
+	require.Len(t, states, 2)
+
+	id, err := states[0].Value("user_id")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), id)
+	name, err := states[0].Value("Name")
+	require.NoError(t, err)
+	require.Equal(t, "a", name)
+	at, err := states[0].Value("at")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), at)
+	tags, err := states[0].Value("tags")
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"x"}, tags)
+
+	marker := stateType.Marker()
+	require.True(t, marker.IsFieldSet(states[0].Pointer(), "Score"))
+	require.True(t, marker.IsFieldSet(states[1].Pointer(), "UserId"))
+	require.True(t, marker.IsFieldSet(states[1].Pointer(), "Score"))
+
+	stateType, states, err = UnmarshalStates(data, WithPresence(PresenceNull))
+	require.NoError(t, err)
+	marker = stateType.Marker()
+	require.True(t, marker.IsFieldSet(states[1].Pointer(), "UserId"))
+	require.False(t, marker.IsFieldSet(states[1].Pointer(), "Score"))
+	require.False(t, marker.IsFieldSet(states[1].Pointer(), "Name"))
+}
+
+func TestUnmarshalStates_DelimitedAndColumnar(t *testing.T) {
+	_, states, err := UnmarshalStates([]byte("id:int,2nd,has\n7,x,true\n"), WithMode(ModeStrict))
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	second, err := states[0].Value("2nd")
+	require.NoError(t, err)
+	require.Equal(t, "x", second)
+	has, err := states[0].Value("Has")
+	require.NoError(t, err)
+	require.Equal(t, true, has)
+
+	data, err := Marshal(typedRows(), WithTypedHeaders(true), WithLayout(ColumnarLayout))
+	require.NoError(t, err)
+	stateType, states, err := UnmarshalStates(data)
+	require.NoError(t, err)
+	require.Len(t, states, 2)
+	require.Equal(t, "Children", stateType.Type().Field(5).Name)
+	score, err := states[0].Value("score")
+	require.NoError(t, err)
+	require.Equal(t, 1.5, score)
+
+	rowType := RowStateType([]string{"a b", "a_b", "", "id:int"})
+	require.Equal(t, "AB", rowType.Type().Field(0).Name)
+	require.Equal(t, "AB2", rowType.Type().Field(1).Name)
+	require.Equal(t, "Column3", rowType.Type().Field(2).Name)
+	require.Equal(t, `csvName:"id"`, string(rowType.Type().Field(3).Tag))
+	require.Equal(t, "Has", rowType.Type().Field(4).Name)
+}
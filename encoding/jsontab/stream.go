@@ -0,0 +1,305 @@
+package jsontab
+
+import (
+	"bufio"
+	"encoding/csv"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	jsmarshal "github.com/viant/structology/encoding/jsontab/marshal"
+	jsunmarshal "github.com/viant/structology/encoding/jsontab/unmarshal"
+)
+
+// Decoder reads table rows one at a time from a JSON table or delimited (CSV/TSV) stream.
+// The form is detected from the first non-space byte, as Unmarshal does.
+type Decoder struct {
+	engine  *jsunmarshal.Engine
+	reader  *bufio.Reader
+	json    *stdjson.Decoder
+	csv     *csv.Reader
+	started bool
+	done    bool
+	err     error
+	headers []string
+	rowType reflect.Type
+	rows    *jsunmarshal.RowReader
+	rowNum  int
+}
+
+// NewDecoder creates a streaming row decoder.
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	return &Decoder{
+		engine: newUnmarshalEngine(resolveOptions(nil, opts)),
+		reader: bufio.NewReader(r),
+	}
+}
+
+// Headers returns the header row, reading it when no row was decoded yet.
+func (d *Decoder) Headers() ([]string, error) {
+	if err := d.start(); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return d.headers, nil
+}
+
+// Next decodes the next row into dest, a pointer to struct; it returns io.EOF after the last row.
+// dest is zeroed before decoding, so presence markers only reflect the current row.
+func (d *Decoder) Next(dest interface{}) error {
+	rv := reflect.ValueOf(dest)
+	if !rv.IsValid() || rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("destination must be non-nil struct pointer")
+	}
+	if err := d.start(); err != nil {
+		return err
+	}
+	if d.rows == nil || d.rowType != rv.Elem().Type() {
+		rows, err := d.engine.NewRowReader(rv.Elem().Type(), d.headers)
+		if err != nil {
+			return d.fail(err)
+		}
+		d.rows, d.rowType = rows, rv.Elem().Type()
+	}
+	row := rv.Elem()
+	for {
+		if d.done {
+			return io.EOF
+		}
+		d.rowNum++
+		row.SetZero()
+		if d.csv != nil {
+			record, err := d.csv.Read()
+			if err != nil {
+				if d.malformed(err) {
+					continue
+				}
+				return d.finish(err)
+			}
+			if err = d.rows.DecodeCSV(record, rv.UnsafePointer(), d.rowNum); err != nil {
+				return d.fail(err)
+			}
+			return nil
+		}
+		if !d.json.More() {
+			if _, err := d.json.Token(); err != nil {
+				return d.fail(err)
+			}
+			return d.finish(io.EOF)
+		}
+		var record stdjson.RawMessage
+		if err := d.json.Decode(&record); err != nil {
+			return d.fail(err)
+		}
+		decoded, err := d.rows.DecodeJSON(record, rv.UnsafePointer(), d.rowNum)
+		if err != nil {
+			return d.fail(err)
+		}
+		if decoded {
+			return nil
+		}
+	}
+}
+
+func (d *Decoder) start() error {
+	if d.started {
+		return d.err
+	}
+	d.started = true
+	first, err := d.peek()
+	if err != nil {
+		if err == io.EOF {
+			if d.engine.Tolerant() {
+				return d.finish(io.EOF)
+			}
+			return d.fail(fmt.Errorf("empty input"))
+		}
+		return d.fail(err)
+	}
+	if first == '{' {
+		return d.fail(fmt.Errorf("columnar layout cannot be decoded row by row"))
+	}
+	if first != '[' {
+		d.csv = d.engine.NewCSVReader(d.reader)
+		d.csv.FieldsPerRecord = -1
+		headers, err := d.csv.Read()
+		if err != nil {
+			if d.malformed(err) {
+				return d.finish(io.EOF)
+			}
+			return d.finish(err)
+		}
+		d.headers = append([]string(nil), headers...)
+		return nil
+	}
+	d.json = stdjson.NewDecoder(d.reader)
+	if _, err = d.json.Token(); err != nil {
+		return d.fail(err)
+	}
+	if !d.json.More() {
+		return d.finish(io.EOF)
+	}
+	var header stdjson.RawMessage
+	if err = d.json.Decode(&header); err != nil {
+		return d.fail(err)
+	}
+	if d.headers, err = d.engine.DecodeHeaders(header); err != nil {
+		return d.fail(err)
+	}
+	if d.headers == nil {
+		return d.finish(io.EOF)
+	}
+	return nil
+}
+
+// peek returns the first non-space byte without consuming it.
+func (d *Decoder) peek() (byte, error) {
+	for {
+		b, err := d.reader.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = d.reader.ReadByte()
+		default:
+			return b[0], nil
+		}
+	}
+}
+
+// malformed reports whether err is a delimited parse error to be skipped in tolerant mode.
+func (d *Decoder) malformed(err error) bool {
+	var parseErr *csv.ParseError
+	return d.engine.Tolerant() && errors.As(err, &parseErr)
+}
+
+// finish ends the stream; io.EOF ends it cleanly, any other error is reported.
+func (d *Decoder) finish(err error) error {
+	d.done = true
+	if err == io.EOF {
+		d.err = io.EOF
+		return io.EOF
+	}
+	return d.fail(err)
+}
+
+func (d *Decoder) fail(err error) error {
+	d.done = true
+	d.err = err
+	return err
+}
+
+// Encoder writes table rows one at a time in the configured output format.
+// The JSON table form produced for a sequence of rows matches Marshal of the same slice.
+type Encoder struct {
+	engine    *jsmarshal.Engine
+	delimited *jsmarshal.Delimited
+	writer    *bufio.Writer
+	rowType   reflect.Type
+	header    bool
+	closed    bool
+	buf       []byte
+}
+
+// NewEncoder creates a streaming row encoder; call Close to terminate the table and flush.
+// Headers precede rows, so PresenceOmit behaves like PresenceNull.
+func NewEncoder(w io.Writer, opts ...Option) *Encoder {
+	cfg := resolveOptions(nil, opts)
+	ret := &Encoder{engine: newMarshalEngine(cfg), writer: bufio.NewWriter(w)}
+	if cfg.OutputFormat != JSONTable {
+		format := delimitedFormat(cfg)
+		ret.delimited = &format
+	}
+	return ret
+}
+
+// WriteHeader writes the header row for the type of row, a struct value or (possibly nil) struct pointer.
+func (e *Encoder) WriteHeader(row interface{}) error {
+	if e.closed {
+		return fmt.Errorf("encoder closed")
+	}
+	if e.header {
+		return fmt.Errorf("header already written")
+	}
+	rowType := reflect.TypeOf(row)
+	for rowType != nil && rowType.Kind() == reflect.Ptr {
+		rowType = rowType.Elem()
+	}
+	if rowType == nil || rowType.Kind() != reflect.Struct {
+		return fmt.Errorf("unsupported row type: %v", rowType)
+	}
+	var err error
+	if e.delimited != nil {
+		e.buf, err = e.engine.AppendDelimitedHeader(e.buf[:0], rowType, *e.delimited)
+	} else {
+		e.buf, err = e.engine.AppendHeader(append(e.buf[:0], '['), rowType)
+	}
+	if err != nil {
+		return err
+	}
+	e.rowType = rowType
+	e.header = true
+	_, err = e.writer.Write(e.buf)
+	return err
+}
+
+// WriteRow writes one row, writing the header first when needed; nil rows are skipped.
+func (e *Encoder) WriteRow(row interface{}) error {
+	if row == nil {
+		return nil
+	}
+	if !e.header {
+		if err := e.WriteHeader(row); err != nil {
+			return err
+		}
+	}
+	if e.closed {
+		return fmt.Errorf("encoder closed")
+	}
+	rv := reflect.ValueOf(row)
+	rowType := rv.Type()
+	for rowType.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+		rowType = rowType.Elem()
+	}
+	if rowType != e.rowType {
+		return fmt.Errorf("row type %s does not match header type %s", rowType, e.rowType)
+	}
+	var err error
+	if e.delimited != nil {
+		e.buf, err = e.engine.AppendDelimitedRow(e.buf[:0], row, *e.delimited)
+	} else {
+		e.buf, err = e.engine.AppendRow(append(e.buf[:0], ','), row)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = e.writer.Write(e.buf)
+	return err
+}
+
+// Close terminates the table and flushes buffered output; it does not close the underlying writer.
+func (e *Encoder) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	if e.delimited != nil {
+		return e.writer.Flush()
+	}
+	var err error
+	if e.header {
+		err = e.writer.WriteByte(']')
+	} else {
+		_, err = e.writer.WriteString("[]")
+	}
+	if err != nil {
+		return err
+	}
+	return e.writer.Flush()
+}
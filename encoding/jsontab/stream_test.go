@@ -0,0 +1,178 @@
+package jsontab
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type streamHas struct {
+	ID   bool
+	Name bool
+}
+
+type streamChild struct {
+	Code string `csvName:"code"`
+}
+
+type streamRec struct {
+	ID    int           `csvName:"id"`
+	Name  *string       `csvName:"name"`
+	At    time.Time     `csvName:"at"`
+	Items []streamChild `csvName:"items"`
+	Has   *streamHas    `setMarker:"true"`
+}
+
+func TestDecoder_JSONTableRows(t *testing.T) {
+	input := ` [["id","name","items"], [1,"a",[["code"],["x"]]], 7, [2], [3,"c",null]] `
+	dec := NewDecoder(strings.NewReader(input))
+	headers, err := dec.Headers()
+	require.NoError(t, err)
+	require.Equal(t, []string{"id", "name", "items"}, headers)
+
+	var row streamRec
+	var ids []int
+	for {
+		err = dec.Next(&row)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, row.ID)
+		require.NotNil(t, row.Has)
+		require.True(t, row.Has.ID)
+		switch row.ID {
+		case 1:
+			require.Equal(t, "a", *row.Name)
+			require.Equal(t, []streamChild{{Code: "x"}}, row.Items)
+			require.True(t, row.Has.Name)
+		case 2:
+			require.Nil(t, row.Name)
+			require.Nil(t, row.Items)
+			require.False(t, row.Has.Name)
+		}
+	}
+	require.Equal(t, []int{1, 2, 3}, ids)
+	require.Equal(t, io.EOF, dec.Next(&row))
+
+	strict := NewDecoder(strings.NewReader(input), WithMode(ModeStrict))
+	require.NoError(t, strict.Next(&row))
+	err = strict.Next(&row)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "record is not an array")
+}
+
+func TestDecoder_CSVRows(t *testing.T) {
+	input := "id,name,at\n1,a,2026-01-02T03:04:05Z\n2,,\n"
+	dec := NewDecoder(strings.NewReader(input))
+	var row streamRec
+	require.NoError(t, dec.Next(&row))
+	require.Equal(t, 1, row.ID)
+	require.Equal(t, "a", *row.Name)
+	require.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), row.At.UTC())
+	require.NoError(t, dec.Next(&row))
+	require.Equal(t, 2, row.ID)
+	require.Nil(t, row.Name)
+	require.True(t, row.At.IsZero())
+	require.Equal(t, io.EOF, dec.Next(&row))
+
+	empty := NewDecoder(strings.NewReader("  "))
+	require.Equal(t, io.EOF, empty.Next(&row))
+	require.Error(t, NewDecoder(strings.NewReader(""), WithMode(ModeStrict)).Next(&row))
+}
+
+type streamFailingReader struct {
+	data string
+}
+
+func (r *streamFailingReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, io.ErrUnexpectedEOF
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestDecoder_CSVTolerantErrors(t *testing.T) {
+	var row streamRec
+	dec := NewDecoder(strings.NewReader("id,name\n1,\"a\"x\n2,b\n"))
+	require.NoError(t, dec.Next(&row))
+	require.Equal(t, 2, row.ID)
+	require.Equal(t, io.EOF, dec.Next(&row))
+
+	dec = NewDecoder(&streamFailingReader{data: "id,name\n1,a\n"})
+	require.NoError(t, dec.Next(&row))
+	require.Equal(t, 1, row.ID)
+	require.ErrorIs(t, dec.Next(&row), io.ErrUnexpectedEOF)
+}
+
+func TestEncoder_MatchesMarshal(t *testing.T) {
+	name := "a<b"
+	rows := []*streamRec{
+		{ID: 1, Name: &name, Items: []streamChild{{Code: "x"}}},
+		nil,
+		{ID: 2, At: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+	expected, err := Marshal(rows)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	for _, row := range rows {
+		require.NoError(t, enc.WriteRow(row))
+	}
+	require.NoError(t, enc.Close())
+	require.Equal(t, string(expected), buf.String())
+
+	var decoded []streamRec
+	dec := NewDecoder(&buf)
+	for {
+		var row streamRec
+		if err = dec.Next(&row); err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		decoded = append(decoded, row)
+	}
+	require.Len(t, decoded, 2)
+	require.Equal(t, "a<b", *decoded[0].Name)
+
+	buf.Reset()
+	enc = NewEncoder(&buf)
+	require.NoError(t, enc.Close())
+	require.Equal(t, "[]", buf.String())
+
+	enc = NewEncoder(&buf)
+	require.NoError(t, enc.WriteHeader((*streamRec)(nil)))
+	require.Error(t, enc.WriteHeader(streamRec{}))
+	require.Error(t, enc.WriteRow(streamChild{}))
+}
+
+func TestEncoder_CSVRoundTrip(t *testing.T) {
+	name := `quoted "x", y`
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, WithOutputFormat(CSV))
+	require.NoError(t, enc.WriteRow(streamRec{ID: 1, Name: &name, Items: []streamChild{{Code: "c"}}}))
+	require.NoError(t, enc.WriteRow(&streamRec{ID: 2, At: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}))
+	require.NoError(t, enc.Close())
+	require.Equal(t, "id,name,at,items\n"+
+		`1,"quoted ""x"", y",,"[[""code""],[""c""]]"`+"\n"+
+		"2,,2026-01-02T03:04:05Z,\n", buf.String())
+
+	dec := NewDecoder(&buf)
+	var row streamRec
+	require.NoError(t, dec.Next(&row))
+	require.Equal(t, name, *row.Name)
+	require.Equal(t, []streamChild{{Code: "c"}}, row.Items)
+	require.True(t, row.At.IsZero())
+	require.NoError(t, dec.Next(&row))
+	require.Equal(t, 2, row.ID)
+	require.Nil(t, row.Name)
+	require.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), row.At.UTC())
+	require.Equal(t, io.EOF, dec.Next(&row))
+}
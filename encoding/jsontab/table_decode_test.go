@@ -0,0 +1,85 @@
+package jsontab
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal_TableScannerCells(t *testing.T) {
+	type child struct {
+		ID   int    `csvName:"id"`
+		Name string `csvName:"name"`
+	}
+	type rec struct {
+		ID     int64             `csvName:"id"`
+		Ratio  float32           `csvName:"ratio"`
+		Name   *string           `csvName:"name"`
+		Active bool              `csvName:"active"`
+		Count  uint8             `csvName:"count"`
+		At     time.Time         `csvName:"at"`
+		Tags   []string          `csvName:"tags"`
+		Owner  *child            `csvName:"owner"`
+		Items  []*child          `csvName:"items"`
+		Attrs  map[string]string `csvName:"attrs"`
+	}
+	data := []byte(` [ ["id","ratio","name","active","count","at","tags","owner","items","attrs","extra"],
+		[9007199254740993, 1.5, "a\"b", true, "7", "2026-01-02T03:04:05Z", ["x","y"], [["id","name"],[1,"o"],[2,"ignored"]], [["name","id"],["i1",10],["i2",11]], {"k":"v"}, {"skipped":[1,2]}],
+		[2, null, null, false, 0, null, null, null, [], null, 1]
+	] `)
+	var out []*rec
+	require.NoError(t, Unmarshal(data, &out))
+	require.Len(t, out, 2)
+	require.Equal(t, int64(9007199254740993), out[0].ID)
+	require.Equal(t, float32(1.5), out[0].Ratio)
+	require.Equal(t, `a"b`, *out[0].Name)
+	require.True(t, out[0].Active)
+	require.Equal(t, uint8(7), out[0].Count)
+	require.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), out[0].At.UTC())
+	require.Equal(t, []string{"x", "y"}, out[0].Tags)
+	require.Equal(t, &child{ID: 1, Name: "o"}, out[0].Owner)
+	require.Equal(t, []*child{{ID: 10, Name: "i1"}, {ID: 11, Name: "i2"}}, out[0].Items)
+	require.Equal(t, map[string]string{"k": "v"}, out[0].Attrs)
+
+	require.Nil(t, out[1].Name)
+	require.Nil(t, out[1].Owner)
+	require.NotNil(t, out[1].Items)
+	require.Empty(t, out[1].Items)
+}
+
+func TestUnmarshal_TableScannerSingleRowAndErrors(t *testing.T) {
+	type rec struct {
+		ID int `csvName:"id"`
+	}
+	var single rec
+	require.NoError(t, Unmarshal([]byte(`[["id"],[1],[2]]`), &single))
+	require.Equal(t, 1, single.ID)
+
+	var ptr *rec
+	require.NoError(t, Unmarshal([]byte(`[["id"],[3]]`), &ptr))
+	require.Equal(t, 3, ptr.ID)
+
+	var rows []rec
+	require.NoError(t, Unmarshal([]byte(`[[1,2],[3]]`), &rows))
+	require.Empty(t, rows)
+	require.Error(t, Unmarshal([]byte(`[[1,2],[3]]`), &rows, WithMode(ModeStrict)))
+	require.ErrorContains(t, Unmarshal([]byte(`[["id"],[1]] x`), &rows), "trailing data")
+	require.ErrorContains(t, Unmarshal([]byte(`[["id"],[true]]`), &rows), "row=1 col=0")
+	require.Error(t, Unmarshal([]byte(`[["id"],[1]`), &rows))
+}
+
+func TestUnmarshal_CSVNestedCellUsesScanner(t *testing.T) {
+	type child struct {
+		ID int `csvName:"id"`
+	}
+	type rec struct {
+		A        int     `csvName:"a"`
+		Children []child `csvName:"children"`
+	}
+	var out []rec
+	require.NoError(t, Unmarshal([]byte("a,children\n1,\"[[\"\"id\"\"],[5],[6]]\"\n2,\"[[\"\"id\"\"],[7]\"\n"), &out))
+	require.Len(t, out, 2)
+	require.Equal(t, []child{{ID: 5}, {ID: 6}}, out[0].Children)
+	require.Nil(t, out[1].Children)
+}
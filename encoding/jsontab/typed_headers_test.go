@@ -0,0 +1,90 @@
+package jsontab
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type typedChild struct {
+	SKU string `csvName:"sku"`
+	Qty int    `csvName:"qty"`
+}
+
+type typedRec struct {
+	ID       int          `csvName:"id"`
+	Name     string       `csvName:"name"`
+	Score    float64      `csvName:"score"`
+	Active   bool         `csvName:"active"`
+	At       time.Time    `csvName:"at"`
+	Children []typedChild `csvName:"children"`
+}
+
+func typedRows() []typedRec {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	return []typedRec{
+		{ID: 1, Name: "a", Score: 1.5, Active: true, At: at, Children: []typedChild{{SKU: "x", Qty: 2}}},
+		{ID: 2, Name: "b", At: at},
+	}
+}
+
+func TestMarshal_TypedHeaders(t *testing.T) {
+	data, err := Marshal(typedRows(), WithTypedHeaders(true))
+	require.NoError(t, err)
+	require.Equal(t, `[["id:int","name:string","score:float","active:bool","at:time","children:table"],`+
+		`[1,"a",1.5,true,"2024-05-01T10:00:00Z",[["sku:string","qty:int"],["x",2]]],`+
+		`[2,"b",0,false,"2024-05-01T10:00:00Z",null]]`, string(data))
+
+	var out []typedRec
+	require.NoError(t, Unmarshal(data, &out, WithMode(ModeStrict)))
+	require.Equal(t, typedRows(), out)
+
+	csv, err := Marshal(typedRows(), WithOutputFormat(CSV), WithTypedHeaders(true))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(csv), "id:int,name:string,score:float,active:bool,at:time,children:table\n"))
+	out = nil
+	require.NoError(t, Unmarshal(csv, &out))
+	require.Equal(t, typedRows(), out)
+}
+
+func TestUnmarshal_TypedHeaderMismatch(t *testing.T) {
+	data := []byte(`[["id:string","name"],["1","a"]]`)
+	var out []typedRec
+	err := Unmarshal(data, &out, WithMode(ModeStrict))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `header "id:string" declares string but field ID is int`)
+
+	out = nil
+	require.NoError(t, Unmarshal([]byte(`[["score:int"],[3]]`), &out, WithMode(ModeStrict)))
+	require.Equal(t, 3.0, out[0].Score)
+}
+
+func TestUnmarshal_GenericMapRows(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	expect := []map[string]interface{}{
+		{"id": int64(1), "name": "a", "score": 1.5, "active": true, "at": at,
+			"children": []map[string]interface{}{{"sku": "x", "qty": int64(2)}}},
+		{"id": int64(2), "name": "b", "score": 0.0, "active": false, "at": at, "children": nil},
+	}
+	for _, opts := range [][]Option{
+		{WithTypedHeaders(true)},
+		{WithTypedHeaders(true), WithOutputFormat(CSV)},
+		{WithTypedHeaders(true), WithLayout(ColumnarLayout)},
+	} {
+		data, err := Marshal(typedRows(), opts...)
+		require.NoError(t, err)
+		var out []map[string]interface{}
+		require.NoError(t, Unmarshal(data, &out), string(data))
+		require.Equal(t, expect, out, string(data))
+	}
+
+	var untyped []map[string]interface{}
+	require.NoError(t, Unmarshal([]byte(`[["id","tags"],[1,["a"]],[2,null]]`), &untyped))
+	require.Equal(t, []map[string]interface{}{{"id": int64(1), "tags": []interface{}{"a"}}, {"id": int64(2), "tags": nil}}, untyped)
+
+	var single map[string]interface{}
+	require.NoError(t, Unmarshal([]byte("id:int,name\n7,x\n"), &single))
+	require.Equal(t, map[string]interface{}{"id": int64(7), "name": "x"}, single)
+}
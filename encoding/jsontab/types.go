@@ -38,6 +38,65 @@ const (
 	ErrorOnMalformed
 )
 
+// OutputFormat selects the encoded table form.
+type OutputFormat int
+
+const (
+	// JSONTable encodes `[[headers],[row]...]`.
+	JSONTable OutputFormat = iota
+	// CSV encodes a header line followed by one line per row; struct children flatten into dotted headers.
+	CSV
+	// TSV is CSV with a tab delimiter.
+	TSV
+)
+
+// Quoting controls quoting of delimited cells.
+type Quoting int
+
+const (
+	// QuoteMinimal quotes cells containing the delimiter, quotes, line breaks or leading spaces.
+	QuoteMinimal Quoting = iota
+	// QuoteAll quotes every cell.
+	QuoteAll
+	// QuoteNone writes cells verbatim and fails on cells that would need quoting.
+	QuoteNone
+)
+
+// SlicePolicy controls struct slice children in delimited output.
+type SlicePolicy int
+
+const (
+	// SliceAsTable writes the child rows as a JSON table cell, which delimited input decodes back.
+	SliceAsTable SlicePolicy = iota
+	// SliceOmit drops struct slice columns.
+	SliceOmit
+)
+
+// PresenceMode controls how marshal writes fields tracked by `setMarker` holders and, when not PresenceIgnore,
+// makes unmarshal treat null scalar cells as unset.
+// Rows without an allocated holder are written as-is.
+type PresenceMode int
+
+const (
+	// PresenceIgnore writes field values regardless of markers.
+	PresenceIgnore PresenceMode = iota
+	// PresenceNull writes null (an empty delimited cell) for fields whose marker is unset.
+	PresenceNull
+	// PresenceOmit behaves like PresenceNull and also drops top-level columns unset in every row.
+	PresenceOmit
+)
+
+// Layout selects how JSON tables arrange cells; Unmarshal accepts both layouts.
+type Layout int
+
+const (
+	// RowLayout writes `[[headers],[row]...]`.
+	RowLayout Layout = iota
+	// ColumnarLayout writes `{"headers":[...],"dict":{...},"columns":[[...],...]}`,
+	// replacing values of repeated string columns with indexes into per-column dictionaries.
+	ColumnarLayout
+)
+
 // Options define jsontab runtime behavior.
 type Options struct {
 	Ctx                 context.Context
@@ -49,6 +108,14 @@ type Options struct {
 	UnknownHeaderPolicy UnknownHeaderPolicy
 	ArityPolicy         ArityPolicy
 	MalformedPolicy     MalformedPolicy
+	OutputFormat        OutputFormat
+	Delimiter           rune
+	Quoting             Quoting
+	SlicePolicy         SlicePolicy
+	Columns             []string
+	Presence            PresenceMode
+	Layout              Layout
+	TypedHeaders        bool
 
 	setTagName    bool
 	setCaseFormat bool
@@ -0,0 +1,259 @@
+package unmarshal
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	jsonunmarshal "github.com/viant/structology/encoding/json/unmarshal"
+	"github.com/viant/structology/encoding/jsontab/internal/plan"
+)
+
+// decodeColumnarInto decodes the `{"headers":[...],"dict":{...},"columns":[[...],...]}` layout.
+func (e *Engine) decodeColumnarInto(data []byte, p *plan.Type, rootVal reflect.Value, rootIsSlice bool) error {
+	headersRaw, dictRaw, columnsRaw, err := e.splitColumnar(data)
+	if err != nil {
+		return err
+	}
+	if headersRaw == nil {
+		if e.malformedPolicy == TolerantMalformed {
+			return nil
+		}
+		return e.derr("", 0, -1, fmt.Errorf("missing headers"))
+	}
+	headers, err := e.scanHeaders(jsonunmarshal.NewScanner(headersRaw, nil))
+	if err != nil {
+		if e.malformedPolicy == TolerantMalformed {
+			return nil
+		}
+		return e.derr("", 0, 0, fmt.Errorf("invalid header row: %w", err))
+	}
+	bound, err := e.boundForHeaders(p, headers, "")
+	if err != nil {
+		return err
+	}
+	dicts, err := e.scanDicts(dictRaw)
+	if err != nil {
+		return err
+	}
+	columns, err := splitColumns(columnsRaw)
+	if err != nil {
+		return e.derr("", 0, -1, err)
+	}
+	if e.arityPolicy == ErrorOnArityMismatch && len(columns) != len(headers) {
+		return e.derr("", 0, -1, fmt.Errorf("arity mismatch: got %d columns want %d", len(columns), len(headers)))
+	}
+
+	count := 0
+	if len(columns) > 0 {
+		if count, err = countValues(columns[0]); err != nil {
+			return e.derr("", 0, 0, err)
+		}
+	}
+	rows := make([]unsafe.Pointer, count)
+	var slice *rowSlice
+	if rootIsSlice {
+		slice = newRowSlice(rootVal.Type(), count)
+		for i := range rows {
+			rows[i] = slice.next()
+		}
+	} else if count > 0 {
+		rows = rows[:1]
+		rows[0] = firstRowTarget(rootVal, p.Type)()
+	}
+	if p.Presence != nil {
+		for _, row := range rows {
+			_ = plan.EnsurePresenceHolder(row, p.Presence)
+		}
+	}
+	for i := range bound {
+		b := &bound[i]
+		if b.col >= len(columns) {
+			continue
+		}
+		if err = e.scanColumn(jsonunmarshal.NewScanner(columns[b.col], nil), p, rows, b, dicts[headers[b.col]], count); err != nil {
+			return err
+		}
+	}
+	if rootIsSlice {
+		rootVal.Set(slice.slice())
+	}
+	return nil
+}
+
+// splitColumnar returns the spans of the headers, dict and columns members.
+func (e *Engine) splitColumnar(data []byte) (headersRaw, dictRaw, columnsRaw []byte, err error) {
+	s := jsonunmarshal.NewScanner(data, nil)
+	s.Consume('{')
+	if !s.Consume('}') {
+		for {
+			if s.Peek() != '"' {
+				return nil, nil, nil, e.derr("", 0, -1, fmt.Errorf("expected columnar key at %d", s.Pos()))
+			}
+			key, err := s.String()
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if !s.Consume(':') {
+				return nil, nil, nil, e.derr("", 0, -1, fmt.Errorf("expected ':' at %d", s.Pos()))
+			}
+			raw, err := s.RawValue()
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			switch key {
+			case "headers":
+				headersRaw = raw
+			case "dict":
+				dictRaw = raw
+			case "columns":
+				columnsRaw = raw
+			}
+			if s.Consume('}') {
+				break
+			}
+			if !s.Consume(',') {
+				return nil, nil, nil, e.derr("", 0, -1, fmt.Errorf("expected ',' at %d", s.Pos()))
+			}
+		}
+	}
+	if !s.EOF() {
+		return nil, nil, nil, fmt.Errorf("unexpected trailing data at %d", s.Pos())
+	}
+	return headersRaw, dictRaw, columnsRaw, nil
+}
+
+// scanColumn decodes one column array into the rows; dictionary columns hold indexes into dict.
+func (e *Engine) scanColumn(s *jsonunmarshal.Scanner, p *plan.Type, rows []unsafe.Pointer, b *boundColumn, dict []string, count int) error {
+	if !s.Consume('[') {
+		return e.derr("", 0, b.col, fmt.Errorf("expected column array"))
+	}
+	n := 0
+	for !s.Consume(']') {
+		if n > 0 && !s.Consume(',') {
+			return e.derr("", n+1, b.col, fmt.Errorf("expected ',' at %d", s.Pos()))
+		}
+		rowNum := n + 1
+		if n >= len(rows) {
+			if err := s.Skip(); err != nil {
+				return err
+			}
+		} else if dict != nil {
+			if err := e.assignDictCell(s, p, rows[n], b, dict, rowNum); err != nil {
+				return err
+			}
+		} else if err := e.scanBoundCell(s, p, rows[n], b, "", rowNum); err != nil {
+			return err
+		}
+		n++
+	}
+	if e.arityPolicy == ErrorOnArityMismatch && n != count {
+		return e.derr("", 0, b.col, fmt.Errorf("arity mismatch: got %d values want %d", n, count))
+	}
+	return nil
+}
+
+func (e *Engine) assignDictCell(s *jsonunmarshal.Scanner, p *plan.Type, rowPtr unsafe.Pointer, b *boundColumn, dict []string, rowNum int) error {
+	if s.Null() {
+		if len(b.parents) == 0 && !e.NullUnset {
+			e.markPresence(p, rowPtr, b.field.StructName)
+		}
+		return nil
+	}
+	raw, err := s.RawValue()
+	if err != nil {
+		return err
+	}
+	index, ok := parseInt64Bytes(raw)
+	if !ok || index < 0 || index >= int64(len(dict)) {
+		if e.malformedPolicy == TolerantMalformed {
+			return nil
+		}
+		return e.derr(joinPath(b.prefix, b.field.StructName), rowNum, b.col, fmt.Errorf("invalid dictionary index %s", raw))
+	}
+	owner, ownerPtr := p, rowPtr
+	if len(b.parents) > 0 {
+		owner, ownerPtr = e.flatTarget(p, rowPtr, b)
+	}
+	if err = e.assignScalar(b.field.XField.Pointer(ownerPtr), b.field.Type, dict[index]); err != nil {
+		return e.derr(joinPath(b.prefix, b.field.StructName), rowNum, b.col, err)
+	}
+	e.markPresence(owner, ownerPtr, b.field.StructName)
+	return nil
+}
+
+func (e *Engine) scanDicts(raw []byte) (map[string][]string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	s := jsonunmarshal.NewScanner(raw, nil)
+	if s.Null() {
+		return nil, nil
+	}
+	if !s.Consume('{') {
+		return nil, e.derr("dict", 0, -1, fmt.Errorf("expected object"))
+	}
+	ret := map[string][]string{}
+	for !s.Consume('}') {
+		if len(ret) > 0 && !s.Consume(',') {
+			return nil, e.derr("dict", 0, -1, fmt.Errorf("expected ',' at %d", s.Pos()))
+		}
+		key, err := s.String()
+		if err != nil {
+			return nil, err
+		}
+		if !s.Consume(':') {
+			return nil, e.derr("dict", 0, -1, fmt.Errorf("expected ':' at %d", s.Pos()))
+		}
+		values, err := e.scanHeaders(s)
+		if err != nil {
+			return nil, e.derr(joinPath("dict", key), 0, -1, err)
+		}
+		if values == nil {
+			values = []string{}
+		}
+		ret[key] = values
+	}
+	return ret, nil
+}
+
+// splitColumns returns the spans of the column arrays.
+func splitColumns(raw []byte) ([][]byte, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	s := jsonunmarshal.NewScanner(raw, nil)
+	if !s.Consume('[') {
+		return nil, fmt.Errorf("expected columns array")
+	}
+	var ret [][]byte
+	for !s.Consume(']') {
+		if len(ret) > 0 && !s.Consume(',') {
+			return nil, fmt.Errorf("expected ',' at %d", s.Pos())
+		}
+		column, err := s.RawValue()
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, column)
+	}
+	return ret, nil
+}
+
+func countValues(column []byte) (int, error) {
+	s := jsonunmarshal.NewScanner(column, nil)
+	if !s.Consume('[') {
+		return 0, fmt.Errorf("expected column array")
+	}
+	n := 0
+	for !s.Consume(']') {
+		if n > 0 && !s.Consume(',') {
+			return 0, fmt.Errorf("expected ',' at %d", s.Pos())
+		}
+		if err := s.Skip(); err != nil {
+			return 0, err
+		}
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,368 @@
+package unmarshal
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+	"unicode"
+
+	jsonunmarshal "github.com/viant/structology/encoding/json/unmarshal"
+	"github.com/viant/structology/encoding/jsontab/internal/plan"
+)
+
+// genericColumn is a header split into its name and optional type annotation.
+type genericColumn struct {
+	name     string
+	typeName string
+}
+
+func isGenericRow(rType reflect.Type) bool {
+	return rType.Kind() == reflect.Map && rType.Key().Kind() == reflect.String &&
+		rType.Elem().Kind() == reflect.Interface && rType.Elem().NumMethod() == 0
+}
+
+func genericColumns(headers []string) []genericColumn {
+	ret := make([]genericColumn, len(headers))
+	for i, header := range headers {
+		ret[i].name, ret[i].typeName = plan.SplitTypedHeader(header)
+	}
+	return ret
+}
+
+// decodeGenericInto decodes any table form into map rows keyed by header name.
+// Typed headers (`id:int`, `at:time`, `children:table`) drive value conversion; untyped cells keep their JSON or text form.
+func (e *Engine) decodeGenericInto(data []byte, rootVal reflect.Value, rootIsSlice bool, rowType reflect.Type) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		if e.malformedPolicy == TolerantMalformed {
+			return nil
+		}
+		return fmt.Errorf("empty input")
+	}
+	var rows []map[string]interface{}
+	var err error
+	switch trimmed[0] {
+	case '[':
+		s := jsonunmarshal.NewScanner(trimmed, nil)
+		if rows, err = e.scanGenericTable(s, ""); err == nil && !s.EOF() {
+			err = fmt.Errorf("unexpected trailing data at %d", s.Pos())
+		}
+	case '{':
+		rows, err = e.decodeGenericColumnar(trimmed)
+	default:
+		if unicode.IsSpace(e.delimiter()) {
+			trimmed = bytes.TrimRight(bytes.TrimLeftFunc(data, unicode.IsSpace), "\r\n")
+		}
+		rows, err = e.decodeGenericCSV(trimmed)
+	}
+	if err != nil {
+		return err
+	}
+	return setGenericRows(rootVal, rootIsSlice, rowType, rows)
+}
+
+func setGenericRows(rootVal reflect.Value, rootIsSlice bool, rowType reflect.Type, rows []map[string]interface{}) error {
+	if !rootIsSlice {
+		if len(rows) == 0 {
+			return nil
+		}
+		return setRootRow(rootVal, reflect.ValueOf(rows[0]).Convert(rowType))
+	}
+	sliceType := rootVal.Type()
+	if sliceType.Kind() == reflect.Ptr {
+		sliceType = sliceType.Elem()
+	}
+	out := reflect.MakeSlice(sliceType, len(rows), len(rows))
+	for i, row := range rows {
+		value := reflect.ValueOf(row).Convert(rowType)
+		if slot := out.Index(i); slot.Kind() == reflect.Ptr {
+			ptr := reflect.New(rowType)
+			ptr.Elem().Set(value)
+			slot.Set(ptr)
+		} else {
+			slot.Set(value)
+		}
+	}
+	return setRootRow(rootVal, out)
+}
+
+func (e *Engine) scanGenericTable(s *jsonunmarshal.Scanner, path string) ([]map[string]interface{}, error) {
+	if !s.Consume('[') {
+		return nil, e.derr(path, 0, -1, fmt.Errorf("expected table array"))
+	}
+	rows := []map[string]interface{}{}
+	if s.Consume(']') {
+		return rows, nil
+	}
+	headers, err := e.scanHeaders(s)
+	if err != nil {
+		if e.malformedPolicy == TolerantMalformed {
+			return rows, skipTableRest(s)
+		}
+		return nil, e.derr(path, 0, 0, fmt.Errorf("invalid header row: %w", err))
+	}
+	columns := genericColumns(headers)
+	for i := 1; ; i++ {
+		if s.Consume(']') {
+			return rows, nil
+		}
+		if !s.Consume(',') {
+			return nil, e.derr(path, i, -1, fmt.Errorf("expected ',' at %d", s.Pos()))
+		}
+		if s.Peek() != '[' {
+			if e.malformedPolicy == TolerantMalformed {
+				if err = s.Skip(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			return nil, e.derr(path, i, -1, fmt.Errorf("record is not an array"))
+		}
+		s.Consume('[')
+		row := make(map[string]interface{}, len(columns))
+		col := 0
+		for !s.Consume(']') {
+			if col > 0 && !s.Consume(',') {
+				return nil, e.derr(path, i, col, fmt.Errorf("expected ',' at %d", s.Pos()))
+			}
+			if col < len(columns) {
+				value, err := e.scanGenericValue(s, columns[col].typeName, joinPath(path, columns[col].name))
+				if err != nil {
+					return nil, e.derr(joinPath(path, columns[col].name), i, col, err)
+				}
+				row[columns[col].name] = value
+			} else if err = s.Skip(); err != nil {
+				return nil, err
+			}
+			col++
+		}
+		if e.arityPolicy == ErrorOnArityMismatch && col != len(columns) {
+			return nil, e.derr(path, i, -1, fmt.Errorf("arity mismatch: got %d want %d", col, len(columns)))
+		}
+		rows = append(rows, row)
+	}
+}
+
+func (e *Engine) scanGenericValue(s *jsonunmarshal.Scanner, typeName, path string) (interface{}, error) {
+	if s.Null() {
+		return nil, nil
+	}
+	switch typeName {
+	case "", plan.TypeJSON:
+		return s.Value()
+	case plan.TypeTable:
+		if s.Peek() != '[' {
+			return nil, fmt.Errorf("expects nested table")
+		}
+		return e.scanGenericTable(s, path)
+	case plan.TypeString:
+		if s.Peek() == '"' {
+			return s.String()
+		}
+	}
+	var text string
+	if s.Peek() == '"' {
+		value, err := s.String()
+		if err != nil {
+			return nil, err
+		}
+		text = value
+	} else {
+		raw, err := s.RawValue()
+		if err != nil {
+			return nil, err
+		}
+		text = string(raw)
+	}
+	return e.genericFromText(text, typeName, path)
+}
+
+// genericFromText converts cell text according to a header type annotation.
+func (e *Engine) genericFromText(text, typeName, path string) (interface{}, error) {
+	switch typeName {
+	case "", plan.TypeString:
+		return text, nil
+	case plan.TypeInt:
+		i, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected int")
+		}
+		return i, nil
+	case plan.TypeFloat:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected float")
+		}
+		return f, nil
+	case plan.TypeBool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("expected bool")
+		}
+		return b, nil
+	case plan.TypeTime:
+		return time.Parse(e.timeLayout, text)
+	case plan.TypeTable:
+		s := jsonunmarshal.NewScanner([]byte(text), nil)
+		rows, err := e.scanGenericTable(s, path)
+		if err == nil && !s.EOF() {
+			err = fmt.Errorf("unexpected trailing data at %d", s.Pos())
+		}
+		return rows, err
+	}
+	var value interface{}
+	if err := stdjson.Unmarshal([]byte(text), &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (e *Engine) decodeGenericCSV(data []byte) ([]map[string]interface{}, error) {
+	reader := e.NewCSVReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	rows := []map[string]interface{}{}
+	headers, err := reader.Read()
+	if err != nil {
+		if e.malformedPolicy == TolerantMalformed {
+			return rows, nil
+		}
+		return nil, e.derr("", 0, -1, err)
+	}
+	columns := genericColumns(headers)
+	for rowNum := 1; ; rowNum++ {
+		record, err := reader.Read()
+		if err != nil {
+			if err == io.EOF || e.malformedPolicy == TolerantMalformed {
+				return rows, nil
+			}
+			return nil, e.derr("", rowNum, -1, err)
+		}
+		if e.arityPolicy == ErrorOnArityMismatch && len(record) != len(columns) {
+			return nil, e.derr("", rowNum, -1, fmt.Errorf("arity mismatch: got %d want %d", len(record), len(columns)))
+		}
+		row := make(map[string]interface{}, len(columns))
+		for col, column := range columns {
+			if col >= len(record) {
+				break
+			}
+			text := record[col]
+			if column.typeName != "" && column.typeName != plan.TypeString && (text == "" || text == "null") {
+				row[column.name] = nil
+				continue
+			}
+			value, err := e.genericFromText(text, column.typeName, column.name)
+			if err != nil {
+				return nil, e.derr(column.name, rowNum, col, err)
+			}
+			row[column.name] = value
+		}
+		rows = append(rows, row)
+	}
+}
+
+func (e *Engine) decodeGenericColumnar(data []byte) ([]map[string]interface{}, error) {
+	rows := []map[string]interface{}{}
+	headersRaw, dictRaw, columnsRaw, err := e.splitColumnar(data)
+	if err != nil {
+		return nil, err
+	}
+	if headersRaw == nil {
+		if e.malformedPolicy == TolerantMalformed {
+			return rows, nil
+		}
+		return nil, e.derr("", 0, -1, fmt.Errorf("missing headers"))
+	}
+	headers, err := e.scanHeaders(jsonunmarshal.NewScanner(headersRaw, nil))
+	if err != nil {
+		if e.malformedPolicy == TolerantMalformed {
+			return rows, nil
+		}
+		return nil, e.derr("", 0, 0, fmt.Errorf("invalid header row: %w", err))
+	}
+	dicts, err := e.scanDicts(dictRaw)
+	if err != nil {
+		return nil, err
+	}
+	columnSpans, err := splitColumns(columnsRaw)
+	if err != nil {
+		return nil, e.derr("", 0, -1, err)
+	}
+	for col, column := range genericColumns(headers) {
+		if col >= len(columnSpans) {
+			break
+		}
+		dict := dicts[headers[col]]
+		s := jsonunmarshal.NewScanner(columnSpans[col], nil)
+		if !s.Consume('[') {
+			return nil, e.derr("", 0, col, fmt.Errorf("expected column array"))
+		}
+		for i := 0; !s.Consume(']'); i++ {
+			if i > 0 && !s.Consume(',') {
+				return nil, e.derr("", i+1, col, fmt.Errorf("expected ',' at %d", s.Pos()))
+			}
+			if i == len(rows) {
+				rows = append(rows, map[string]interface{}{})
+			}
+			value, err := e.scanGenericColumnarCell(s, dict, column)
+			if err != nil {
+				return nil, e.derr(column.name, i+1, col, err)
+			}
+			rows[i][column.name] = value
+		}
+	}
+	return rows, nil
+}
+
+// scanGenericColumnarCell resolves dictionary indexes before converting a columnar cell.
+func (e *Engine) scanGenericColumnarCell(s *jsonunmarshal.Scanner, dict []string, column genericColumn) (interface{}, error) {
+	if dict == nil {
+		return e.scanGenericValue(s, column.typeName, column.name)
+	}
+	if s.Null() {
+		return nil, nil
+	}
+	raw, err := s.RawValue()
+	if err != nil {
+		return nil, err
+	}
+	index, ok := parseInt64Bytes(raw)
+	if !ok || index < 0 || index >= int64(len(dict)) {
+		return nil, fmt.Errorf("invalid dictionary index %s", raw)
+	}
+	return e.genericFromText(dict[index], column.typeName, column.name)
+}
+
+// Headers returns the header row of a JSON table, columnar table or delimited input without decoding records.
+func (e *Engine) Headers(data []byte) ([]string, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("empty input")
+	}
+	switch trimmed[0] {
+	case '[':
+		s := jsonunmarshal.NewScanner(trimmed, nil)
+		s.Consume('[')
+		if s.Consume(']') {
+			return nil, nil
+		}
+		return e.scanHeaders(s)
+	case '{':
+		headersRaw, _, _, err := e.splitColumnar(trimmed)
+		if err != nil || headersRaw == nil {
+			return nil, err
+		}
+		return e.scanHeaders(jsonunmarshal.NewScanner(headersRaw, nil))
+	}
+	if unicode.IsSpace(e.delimiter()) {
+		trimmed = bytes.TrimLeftFunc(data, unicode.IsSpace)
+	}
+	headers, err := e.NewCSVReader(bytes.NewReader(trimmed)).Read()
+	if err != nil {
+		return nil, e.derr("", 0, -1, err)
+	}
+	return headers, nil
+}
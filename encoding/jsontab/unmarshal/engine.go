@@ -12,6 +12,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 	"unsafe"
 
 	"github.com/viant/structology/encoding/jsontab/internal/plan"
@@ -50,6 +51,13 @@ type Engine struct {
 	arityPolicy         ArityPolicy
 	malformedPolicy     MalformedPolicy
 	bindCache           sync.Map // map[bindCacheKey][]boundColumn
+	// Delimiter separates delimited input cells; zero selects ','.
+	Delimiter rune
+	// Columns restricts decoding to the selected headers; dotted columns select child table columns.
+	Columns []string
+	// NullUnset leaves presence markers of null scalar cells and empty or null delimited cells unset,
+	// mirroring marshal presence modes.
+	NullUnset bool
 }
 
 func New(tagName, caseKey string, compileName func(string) string, timeLayout string, unknown UnknownHeaderPolicy, arity ArityPolicy, malformed MalformedPolicy) *Engine {
@@ -127,9 +135,15 @@ func (e *Engine) Unmarshal(data []byte, dest interface{}) error {
 		elemType = target
 	}
 	if elemType.Kind() != reflect.Struct {
+		if isGenericRow(elemType) {
+			return e.decodeGenericInto(data, rootVal, rootIsSlice, elemType)
+		}
 		return fmt.Errorf("unsupported destination type: %s", target)
 	}
-	p := plan.For(elemType, e.tagName, e.caseKey, e.compileName)
+	p, err := e.planFor(elemType)
+	if err != nil {
+		return err
+	}
 
 	trimmed := bytes.TrimSpace(data)
 	if len(trimmed) == 0 {
@@ -139,45 +153,18 @@ func (e *Engine) Unmarshal(data []byte, dest interface{}) error {
 		return fmt.Errorf("empty input")
 	}
 
+	if trimmed[0] == '{' {
+		return e.decodeColumnarInto(trimmed, p, rootVal, rootIsSlice)
+	}
 	if trimmed[0] != '[' {
+		if unicode.IsSpace(e.delimiter()) {
+			// keep trailing empty cells of whitespace-delimited rows
+			trimmed = bytes.TrimRight(bytes.TrimLeftFunc(data, unicode.IsSpace), "\r\n")
+		}
 		return e.decodeCSVInto(trimmed, p, rootVal, rootIsSlice, "")
 	}
 
-	var parsed interface{}
-	if err := stdjson.Unmarshal(trimmed, &parsed); err != nil {
-		return err
-	}
-	table, ok := parsed.([]interface{})
-	if !ok {
-		return fmt.Errorf("expected table array")
-	}
-	rows, err := e.decodeTable(p, table, "", 0)
-	if err != nil {
-		return err
-	}
-	return setRootRows(rootVal, rootIsSlice, rows)
-}
-
-func setRootRows(rootVal reflect.Value, rootIsSlice bool, rows []reflect.Value) error {
-	if rootIsSlice {
-		sliceType := rootVal.Type()
-		out := reflect.MakeSlice(sliceType, 0, len(rows))
-		for _, row := range rows {
-			if sliceType.Elem().Kind() == reflect.Ptr {
-				ptr := reflect.New(row.Type())
-				ptr.Elem().Set(row)
-				out = reflect.Append(out, ptr)
-			} else {
-				out = reflect.Append(out, row)
-			}
-		}
-		rootVal.Set(out)
-		return nil
-	}
-	if len(rows) == 0 {
-		return nil
-	}
-	return setRootRow(rootVal, rows[0])
+	return e.decodeJSONTableInto(trimmed, p, rootVal, rootIsSlice)
 }
 
 func setRootRow(rootVal reflect.Value, row reflect.Value) error {
@@ -195,46 +182,132 @@ func setRootRow(rootVal reflect.Value, row reflect.Value) error {
 type boundColumn struct {
 	field *plan.Field
 	col   int
+	// parents lead from the row to the struct holding field for flattened `parent.child` headers.
+	parents []*plan.Field
+	prefix  string
 }
 
 type bindCacheKey struct {
-	rType      reflect.Type
+	plan       *plan.Type
 	headersSig string
 	unknown    UnknownHeaderPolicy
 }
 
 func (e *Engine) boundForHeaders(p *plan.Type, headers []string, path string) ([]boundColumn, error) {
 	sig := strings.Join(headers, "\x1f")
-	key := bindCacheKey{rType: p.Type, headersSig: sig, unknown: e.unknownHeaderPolicy}
+	key := bindCacheKey{plan: p, headersSig: sig, unknown: e.unknownHeaderPolicy}
 	if v, ok := e.bindCache.Load(key); ok {
 		return v.([]boundColumn), nil
 	}
 	bound := make([]boundColumn, 0, len(headers))
 	for i, h := range headers {
-		f, ok := p.HeaderToField[h]
-		if !ok {
+		column, found := bindHeader(p, h)
+		declared := ""
+		if !found {
+			var name string
+			if name, declared = plan.SplitTypedHeader(h); declared != "" {
+				column, found = bindHeader(p, name)
+				h = name
+			}
+		}
+		if !found {
+			if p.Source != nil && isKnownHeader(p.Source, h) {
+				continue
+			}
 			if e.unknownHeaderPolicy == ErrorOnUnknownHeader {
-				return nil, e.derr(path, 0, i, fmt.Errorf("unknown header %q", h))
+				return nil, e.derr(path, 0, i, fmt.Errorf("unknown header %q", headers[i]))
 			}
 			continue
 		}
-		bound = append(bound, boundColumn{field: f, col: i})
+		if declared != "" && e.malformedPolicy == ErrorOnMalformed && !plan.CompatibleType(declared, column.field) {
+			return nil, e.derr(path, 0, i, fmt.Errorf("header %q declares %s but field %s is %s", headers[i], declared, column.field.StructName, column.field.TypeName()))
+		}
+		column.col = i
+		bound = append(bound, column)
 	}
 	e.bindCache.Store(key, bound)
 	return bound, nil
 }
 
+// bindHeader binds a plain or dotted header to its field.
+func bindHeader(p *plan.Type, header string) (boundColumn, bool) {
+	if f, ok := p.HeaderToField[header]; ok {
+		return boundColumn{field: f}, true
+	}
+	return resolveFlatHeader(p, header, nil, "")
+}
+
+// isKnownHeader reports whether header names a column of p, including flattened child columns.
+func isKnownHeader(p *plan.Type, header string) bool {
+	_, ok := bindHeader(p, header)
+	return ok
+}
+
+func (e *Engine) planFor(rowType reflect.Type) (*plan.Type, error) {
+	return plan.Project(plan.For(rowType, e.tagName, e.caseKey, e.compileName), e.Columns)
+}
+
+// resolveFlatHeader binds a dotted header such as `address.city` to a field of a nested struct.
+func resolveFlatHeader(p *plan.Type, header string, parents []*plan.Field, prefix string) (boundColumn, bool) {
+	for i := 0; i < len(header); i++ {
+		if header[i] != '.' {
+			continue
+		}
+		parent, ok := p.HeaderToField[header[:i]]
+		if !ok || parent.Kind != plan.FieldStruct {
+			continue
+		}
+		chain := append(parents[:len(parents):len(parents)], parent)
+		childPrefix := joinPath(prefix, parent.StructName)
+		if f, ok := parent.Child.HeaderToField[header[i+1:]]; ok {
+			return boundColumn{field: f, parents: chain, prefix: childPrefix}, true
+		}
+		if column, ok := resolveFlatHeader(parent.Child, header[i+1:], chain, childPrefix); ok {
+			return column, true
+		}
+	}
+	return boundColumn{}, false
+}
+
+// flatTarget returns the plan and storage of the struct holding a flattened column,
+// allocating nil parents and marking them present.
+func (e *Engine) flatTarget(p *plan.Type, rowPtr unsafe.Pointer, b *boundColumn) (*plan.Type, unsafe.Pointer) {
+	for _, parent := range b.parents {
+		ptr := parent.XField.Pointer(rowPtr)
+		for t := parent.Type; t.Kind() == reflect.Ptr; t = t.Elem() {
+			ptr = xunsafe.SafeDerefPointer(ptr, t)
+		}
+		e.markPresence(p, rowPtr, parent.StructName)
+		p, rowPtr = parent.Child, ptr
+	}
+	return p, rowPtr
+}
+
+// NewCSVReader creates a reader for delimited input configured like the engine.
+func (e *Engine) NewCSVReader(r io.Reader) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.Comma = e.delimiter()
+	reader.TrimLeadingSpace = !unicode.IsSpace(reader.Comma)
+	reader.ReuseRecord = true
+	return reader
+}
+
+func (e *Engine) delimiter() rune {
+	if e.Delimiter == 0 {
+		return ','
+	}
+	return e.Delimiter
+}
+
 func (e *Engine) decodeCSVInto(data []byte, p *plan.Type, rootVal reflect.Value, rootIsSlice bool, path string) error {
-	if bytes.IndexByte(data, '"') < 0 {
+	if bytes.IndexByte(data, '"') < 0 && e.delimiter() == ',' {
 		handled, err := e.decodeSimpleCSVInto(data, p, rootVal, rootIsSlice, path)
 		if handled || err != nil {
 			return err
 		}
 	}
 
-	reader := csv.NewReader(bytes.NewReader(data))
-	reader.TrimLeadingSpace = true
-	reader.ReuseRecord = true
+	reader := e.NewCSVReader(bytes.NewReader(data))
 
 	headers, err := reader.Read()
 	if err != nil {
@@ -433,86 +506,40 @@ func (e *Engine) applySimpleCSVRecord(p *plan.Type, rowPtr unsafe.Pointer, line
 		}
 		if bi < len(bound) && bound[bi].col == col {
 			field := bound[bi].field
-			fieldPtr := field.XField.Pointer(rowPtr)
 			raw := line[start:i]
-			switch field.Kind {
-			case plan.FieldScalar:
-				if err := e.assignScalarBytes(fieldPtr, field.Type, raw); err != nil {
-					return e.derr(joinPath(path, field.StructName), rowNum, col, err)
-				}
-				e.markPresence(p, rowPtr, field.StructName)
-			case plan.FieldStruct, plan.FieldSliceStruct:
+			owner, ownerPtr, ownerPath := p, rowPtr, path
+			if len(bound[bi].parents) > 0 {
 				if len(raw) == 0 || isNullBytes(raw) {
 					bi++
 					col++
 					start = i + 1
 					continue
 				}
-				if raw[0] != '[' {
-					if e.malformedPolicy == TolerantMalformed {
-						bi++
-						col++
-						start = i + 1
-						continue
-					}
-					return e.derr(joinPath(path, field.StructName), rowNum, col, fmt.Errorf("expects nested table"))
+				owner, ownerPtr = e.flatTarget(p, rowPtr, &bound[bi])
+				ownerPath = joinPath(path, bound[bi].prefix)
+			}
+			fieldPtr := field.XField.Pointer(ownerPtr)
+			switch field.Kind {
+			case plan.FieldScalar:
+				if err := e.assignCellBytes(fieldPtr, field, raw); err != nil {
+					return e.derr(joinPath(ownerPath, field.StructName), rowNum, col, err)
 				}
-				var parsed interface{}
-				if parseErr := stdjson.Unmarshal(raw, &parsed); parseErr != nil {
-					if e.malformedPolicy == TolerantMalformed {
-						bi++
-						col++
-						start = i + 1
-						continue
-					}
-					return e.derr(joinPath(path, field.StructName), rowNum, col, parseErr)
+				if !e.NullUnset || len(raw) > 0 && !isNullBytes(raw) {
+					e.markPresence(owner, ownerPtr, field.StructName)
+				}
+			case plan.FieldStruct, plan.FieldSliceStruct:
+				if len(raw) == 0 || isNullBytes(raw) {
+					break
 				}
-				childTable, ok := parsed.([]interface{})
-				if !ok {
+				if raw[0] != '[' {
 					if e.malformedPolicy == TolerantMalformed {
-						bi++
-						col++
-						start = i + 1
-						continue
+						break
 					}
-					return e.derr(joinPath(path, field.StructName), rowNum, col, fmt.Errorf("expects nested table"))
+					return e.derr(joinPath(ownerPath, field.StructName), rowNum, col, fmt.Errorf("expects nested table"))
 				}
-				fieldPath := joinPath(path, field.StructName)
-				if field.Kind == plan.FieldStruct {
-					childRows, decErr := e.decodeTable(field.Child, childTable, fieldPath, rowNum)
-					if decErr != nil {
-						return decErr
-					}
-					if len(childRows) == 0 {
-						bi++
-						col++
-						start = i + 1
-						continue
-					}
-					if field.Type.Kind() == reflect.Ptr {
-						target := xunsafe.SafeDerefPointer(fieldPtr, field.Type)
-						reflect.NewAt(field.Type.Elem(), target).Elem().Set(childRows[0])
-					} else {
-						reflect.NewAt(field.Type, fieldPtr).Elem().Set(childRows[0])
-					}
-				} else {
-					childRows, decErr := e.decodeTable(field.Child, childTable, fieldPath, rowNum)
-					if decErr != nil {
-						return decErr
-					}
-					sliceVal := reflect.MakeSlice(field.Type, 0, len(childRows))
-					for _, child := range childRows {
-						if field.Type.Elem().Kind() == reflect.Ptr {
-							item := reflect.New(child.Type())
-							item.Elem().Set(child)
-							sliceVal = reflect.Append(sliceVal, item)
-						} else {
-							sliceVal = reflect.Append(sliceVal, child)
-						}
-					}
-					reflect.NewAt(field.Type, fieldPtr).Elem().Set(sliceVal)
+				if err := e.decodeNestedCell(raw, owner, ownerPtr, field, ownerPath, rowNum, col); err != nil {
+					return err
 				}
-				e.markPresence(p, rowPtr, field.StructName)
 			}
 			bi++
 		}
@@ -522,6 +549,29 @@ func (e *Engine) applySimpleCSVRecord(p *plan.Type, rowPtr unsafe.Pointer, line
 	return nil
 }
 
+// assignCellBytes assigns delimited cell text to a scalar field.
+// JSON cells hold either a JSON value or, for values encoded as JSON strings, the unquoted text,
+// so text that does not decode as JSON is retried as a string; text-marshaler cells always hold the unquoted text.
+func (e *Engine) assignCellBytes(ptr unsafe.Pointer, field *plan.Field, raw []byte) error {
+	if field.Cell == plan.CellValue {
+		return e.assignScalarBytes(ptr, field.Type, raw)
+	}
+	if len(raw) == 0 || isNullBytes(raw) {
+		return nil
+	}
+	target := reflect.NewAt(field.Type, ptr).Interface()
+	if field.Cell == plan.CellJSON && stdjson.Valid(raw) {
+		if err := stdjson.Unmarshal(raw, target); err == nil {
+			return nil
+		}
+	}
+	quoted, err := stdjson.Marshal(string(raw))
+	if err != nil {
+		return err
+	}
+	return stdjson.Unmarshal(quoted, target)
+}
+
 func (e *Engine) assignScalarBytes(ptr unsafe.Pointer, rType reflect.Type, raw []byte) error {
 	if rType.Kind() == reflect.Ptr {
 		if len(raw) == 0 || isNullBytes(raw) {
@@ -721,19 +771,30 @@ func estimateCSVDataRows(data []byte) int {
 }
 
 func (e *Engine) applyCSVRecord(p *plan.Type, rowPtr unsafe.Pointer, record []string, bound []boundColumn, rowNum int, path string) error {
-	for _, b := range bound {
+	for i := range bound {
+		b := &bound[i]
 		if b.col >= len(record) {
 			continue
 		}
 		field := b.field
 		raw := record[b.col]
-		fieldPtr := field.XField.Pointer(rowPtr)
+		owner, ownerPtr, ownerPath := p, rowPtr, path
+		if len(b.parents) > 0 {
+			if raw == "" || raw == "null" {
+				continue
+			}
+			owner, ownerPtr = e.flatTarget(p, rowPtr, b)
+			ownerPath = joinPath(path, b.prefix)
+		}
+		fieldPtr := field.XField.Pointer(ownerPtr)
 		switch field.Kind {
 		case plan.FieldScalar:
-			if err := e.assignScalarString(fieldPtr, field.Type, raw); err != nil {
-				return e.derr(joinPath(path, field.StructName), rowNum, b.col, err)
+			if err := e.assignCellBytes(fieldPtr, field, []byte(raw)); err != nil {
+				return e.derr(joinPath(ownerPath, field.StructName), rowNum, b.col, err)
+			}
+			if !e.NullUnset || raw != "" && raw != "null" {
+				e.markPresence(owner, ownerPtr, field.StructName)
 			}
-			e.markPresence(p, rowPtr, field.StructName)
 		case plan.FieldStruct, plan.FieldSliceStruct:
 			if raw == "" || raw == "null" {
 				continue
@@ -742,176 +803,16 @@ func (e *Engine) applyCSVRecord(p *plan.Type, rowPtr unsafe.Pointer, record []st
 				if e.malformedPolicy == TolerantMalformed {
 					continue
 				}
-				return e.derr(joinPath(path, field.StructName), rowNum, b.col, fmt.Errorf("expects nested table"))
+				return e.derr(joinPath(ownerPath, field.StructName), rowNum, b.col, fmt.Errorf("expects nested table"))
 			}
-			var parsed interface{}
-			if parseErr := stdjson.Unmarshal([]byte(raw), &parsed); parseErr != nil {
-				if e.malformedPolicy == TolerantMalformed {
-					continue
-				}
-				return e.derr(joinPath(path, field.StructName), rowNum, b.col, parseErr)
-			}
-			childTable, ok := parsed.([]interface{})
-			if !ok {
-				if e.malformedPolicy == TolerantMalformed {
-					continue
-				}
-				return e.derr(joinPath(path, field.StructName), rowNum, b.col, fmt.Errorf("expects nested table"))
-			}
-			fieldPath := joinPath(path, field.StructName)
-			if field.Kind == plan.FieldStruct {
-				childRows, decErr := e.decodeTable(field.Child, childTable, fieldPath, rowNum)
-				if decErr != nil {
-					return decErr
-				}
-				if len(childRows) == 0 {
-					continue
-				}
-				if field.Type.Kind() == reflect.Ptr {
-					target := xunsafe.SafeDerefPointer(fieldPtr, field.Type)
-					reflect.NewAt(field.Type.Elem(), target).Elem().Set(childRows[0])
-				} else {
-					reflect.NewAt(field.Type, fieldPtr).Elem().Set(childRows[0])
-				}
-			} else {
-				childRows, decErr := e.decodeTable(field.Child, childTable, fieldPath, rowNum)
-				if decErr != nil {
-					return decErr
-				}
-				sliceVal := reflect.MakeSlice(field.Type, 0, len(childRows))
-				for _, child := range childRows {
-					if field.Type.Elem().Kind() == reflect.Ptr {
-						item := reflect.New(child.Type())
-						item.Elem().Set(child)
-						sliceVal = reflect.Append(sliceVal, item)
-					} else {
-						sliceVal = reflect.Append(sliceVal, child)
-					}
-				}
-				reflect.NewAt(field.Type, fieldPtr).Elem().Set(sliceVal)
+			if err := e.decodeNestedCell([]byte(raw), owner, ownerPtr, field, ownerPath, rowNum, b.col); err != nil {
+				return err
 			}
-			e.markPresence(p, rowPtr, field.StructName)
 		}
 	}
 	return nil
 }
 
-func (e *Engine) decodeTable(p *plan.Type, table []interface{}, path string, baseRow int) ([]reflect.Value, error) {
-	if len(table) == 0 {
-		return nil, nil
-	}
-	headers, err := toStringSlice(table[0])
-	if err != nil {
-		if e.malformedPolicy == TolerantMalformed {
-			return nil, nil
-		}
-		return nil, e.derr(path, baseRow, 0, fmt.Errorf("invalid header row: %w", err))
-	}
-	indexByHeader := make(map[string]int, len(headers))
-	for i, h := range headers {
-		indexByHeader[h] = i
-	}
-	if e.unknownHeaderPolicy == ErrorOnUnknownHeader {
-		for i, h := range headers {
-			if _, ok := p.HeaderToField[h]; !ok {
-				return nil, e.derr(path, baseRow, i, fmt.Errorf("unknown header %q", h))
-			}
-		}
-	}
-
-	ret := make([]reflect.Value, 0, len(table)-1)
-	for i := 1; i < len(table); i++ {
-		record, ok := table[i].([]interface{})
-		if !ok {
-			if e.malformedPolicy == TolerantMalformed {
-				continue
-			}
-			return nil, e.derr(path, baseRow+i, -1, fmt.Errorf("record is not an array"))
-		}
-		if e.arityPolicy == ErrorOnArityMismatch && len(record) != len(headers) {
-			return nil, e.derr(path, baseRow+i, -1, fmt.Errorf("arity mismatch: got %d want %d", len(record), len(headers)))
-		}
-
-		row := reflect.New(p.Type).Elem()
-		rowPtr := unsafe.Pointer(row.Addr().Pointer())
-		if p.Presence != nil {
-			_ = plan.EnsurePresenceHolder(rowPtr, p.Presence)
-		}
-
-		for _, field := range p.Fields {
-			idx, ok := indexByHeader[field.HeaderName]
-			if !ok || idx >= len(record) {
-				continue
-			}
-			val := record[idx]
-			fieldPtr := field.XField.Pointer(rowPtr)
-			switch field.Kind {
-			case plan.FieldScalar:
-				if err = e.assignScalar(fieldPtr, field.Type, val); err != nil {
-					return nil, e.derr(joinPath(path, field.StructName), baseRow+i, idx, err)
-				}
-				e.markPresence(p, rowPtr, field.StructName)
-			case plan.FieldStruct:
-				if val == nil {
-					continue
-				}
-				childTable, ok := val.([]interface{})
-				if !ok {
-					if e.malformedPolicy == TolerantMalformed {
-						continue
-					}
-					return nil, e.derr(joinPath(path, field.StructName), baseRow+i, idx, fmt.Errorf("expects nested table"))
-				}
-				fieldPath := joinPath(path, field.StructName)
-				rows, decErr := e.decodeTable(field.Child, childTable, fieldPath, baseRow+i)
-				if decErr != nil {
-					return nil, decErr
-				}
-				if len(rows) == 0 {
-					continue
-				}
-				if field.Type.Kind() == reflect.Ptr {
-					target := xunsafe.SafeDerefPointer(fieldPtr, field.Type)
-					reflect.NewAt(field.Type.Elem(), target).Elem().Set(rows[0])
-				} else {
-					reflect.NewAt(field.Type, fieldPtr).Elem().Set(rows[0])
-				}
-				e.markPresence(p, rowPtr, field.StructName)
-			case plan.FieldSliceStruct:
-				if val == nil {
-					continue
-				}
-				childTable, ok := val.([]interface{})
-				if !ok {
-					if e.malformedPolicy == TolerantMalformed {
-						continue
-					}
-					return nil, e.derr(joinPath(path, field.StructName), baseRow+i, idx, fmt.Errorf("expects nested table"))
-				}
-				fieldPath := joinPath(path, field.StructName)
-				rows, decErr := e.decodeTable(field.Child, childTable, fieldPath, baseRow+i)
-				if decErr != nil {
-					return nil, decErr
-				}
-				sliceVal := reflect.MakeSlice(field.Type, 0, len(rows))
-				for _, child := range rows {
-					if field.Type.Elem().Kind() == reflect.Ptr {
-						item := reflect.New(child.Type())
-						item.Elem().Set(child)
-						sliceVal = reflect.Append(sliceVal, item)
-					} else {
-						sliceVal = reflect.Append(sliceVal, child)
-					}
-				}
-				reflect.NewAt(field.Type, fieldPtr).Elem().Set(sliceVal)
-				e.markPresence(p, rowPtr, field.StructName)
-			}
-		}
-		ret = append(ret, row)
-	}
-	return ret, nil
-}
-
 func (e *Engine) markPresence(p *plan.Type, rowPtr unsafe.Pointer, fieldName string) {
 	if p == nil || p.Presence == nil {
 		return
@@ -934,60 +835,6 @@ func joinPath(path, field string) string {
 	return path + "." + field
 }
 
-func (e *Engine) assignScalarString(ptr unsafe.Pointer, rType reflect.Type, raw string) error {
-	if rType.Kind() == reflect.Ptr {
-		if raw == "" || raw == "null" {
-			return nil
-		}
-		target := xunsafe.SafeDerefPointer(ptr, rType)
-		return e.assignScalarString(target, rType.Elem(), raw)
-	}
-	if raw == "" || raw == "null" {
-		return nil
-	}
-
-	switch rType.Kind() {
-	case reflect.String:
-		*xunsafe.AsStringPtr(ptr) = raw
-		return nil
-	case reflect.Bool:
-		b, err := strconv.ParseBool(raw)
-		if err != nil {
-			return fmt.Errorf("expected bool")
-		}
-		*xunsafe.AsBoolPtr(ptr) = b
-		return nil
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		i, err := strconv.ParseInt(raw, 10, 64)
-		if err != nil {
-			return fmt.Errorf("expected number")
-		}
-		return setIntKind(ptr, rType.Kind(), i)
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		u, err := strconv.ParseUint(raw, 10, 64)
-		if err != nil {
-			return fmt.Errorf("expected number")
-		}
-		return setUintKind(ptr, rType.Kind(), u)
-	case reflect.Float32, reflect.Float64:
-		f, err := strconv.ParseFloat(raw, 64)
-		if err != nil {
-			return fmt.Errorf("expected number")
-		}
-		return setFloatKind(ptr, rType.Kind(), f)
-	case reflect.Struct:
-		if rType == timeType {
-			tm, err := time.Parse(e.timeLayout, raw)
-			if err != nil {
-				return err
-			}
-			*xunsafe.AsTimePtr(ptr) = tm
-			return nil
-		}
-	}
-	return e.assignScalar(ptr, rType, raw)
-}
-
 func (e *Engine) assignScalar(ptr unsafe.Pointer, rType reflect.Type, value interface{}) error {
 	if rType.Kind() == reflect.Ptr {
 		if value == nil {
@@ -1068,22 +915,6 @@ func (e *Engine) assignScalar(ptr unsafe.Pointer, rType reflect.Type, value inte
 	return nil
 }
 
-func toStringSlice(v interface{}) ([]string, error) {
-	arr, ok := v.([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("expected []interface{}")
-	}
-	ret := make([]string, 0, len(arr))
-	for _, item := range arr {
-		s, ok := item.(string)
-		if !ok {
-			return nil, fmt.Errorf("expected string header")
-		}
-		ret = append(ret, s)
-	}
-	return ret, nil
-}
-
 func asInt64(v interface{}) (int64, error) {
 	switch a := v.(type) {
 	case float64:
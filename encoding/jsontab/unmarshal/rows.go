@@ -0,0 +1,88 @@
+package unmarshal
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	jsonunmarshal "github.com/viant/structology/encoding/json/unmarshal"
+	"github.com/viant/structology/encoding/jsontab/internal/plan"
+)
+
+// RowReader decodes individual records against one header row, for streaming callers.
+type RowReader struct {
+	e       *Engine
+	p       *plan.Type
+	bound   []boundColumn
+	headers int
+}
+
+// NewRowReader binds headers to the fields of rowType.
+func (e *Engine) NewRowReader(rowType reflect.Type, headers []string) (*RowReader, error) {
+	if rowType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported row type: %s", rowType)
+	}
+	p, err := e.planFor(rowType)
+	if err != nil {
+		return nil, err
+	}
+	bound, err := e.boundForHeaders(p, headers, "")
+	if err != nil {
+		return nil, err
+	}
+	return &RowReader{e: e, p: p, bound: bound, headers: len(headers)}, nil
+}
+
+// DecodeHeaders parses a JSON header row; in tolerant mode an invalid header row yields nil headers.
+func (e *Engine) DecodeHeaders(raw []byte) ([]string, error) {
+	s := jsonunmarshal.NewScanner(raw, nil)
+	headers, err := e.scanHeaders(s)
+	if err == nil && !s.EOF() {
+		err = fmt.Errorf("unexpected trailing data at %d", s.Pos())
+	}
+	if err != nil {
+		if e.malformedPolicy == TolerantMalformed {
+			return nil, nil
+		}
+		return nil, e.derr("", 0, 0, fmt.Errorf("invalid header row: %w", err))
+	}
+	return headers, nil
+}
+
+// DecodeJSON decodes a JSON record array into the zeroed struct at rowPtr.
+// It reports false when a malformed record was skipped in tolerant mode.
+func (r *RowReader) DecodeJSON(record []byte, rowPtr unsafe.Pointer, rowNum int) (bool, error) {
+	s := jsonunmarshal.NewScanner(record, nil)
+	if s.Peek() != '[' {
+		if r.e.malformedPolicy == TolerantMalformed {
+			return false, nil
+		}
+		return false, r.e.derr("", rowNum, -1, fmt.Errorf("record is not an array"))
+	}
+	if r.p.Presence != nil {
+		_ = plan.EnsurePresenceHolder(rowPtr, r.p.Presence)
+	}
+	if err := r.e.scanRecord(s, r.p, rowPtr, r.bound, r.headers, "", rowNum); err != nil {
+		return false, err
+	}
+	if !s.EOF() {
+		return false, r.e.derr("", rowNum, -1, fmt.Errorf("unexpected trailing data at %d", s.Pos()))
+	}
+	return true, nil
+}
+
+// DecodeCSV decodes a CSV record into the zeroed struct at rowPtr.
+func (r *RowReader) DecodeCSV(record []string, rowPtr unsafe.Pointer, rowNum int) error {
+	if r.e.arityPolicy == ErrorOnArityMismatch && len(record) != r.headers {
+		return r.e.derr("", rowNum, -1, fmt.Errorf("arity mismatch: got %d want %d", len(record), r.headers))
+	}
+	if r.p.Presence != nil {
+		_ = plan.EnsurePresenceHolder(rowPtr, r.p.Presence)
+	}
+	return r.e.applyCSVRecord(r.p, rowPtr, record, r.bound, rowNum, "")
+}
+
+// Tolerant reports whether malformed input is skipped rather than reported.
+func (e *Engine) Tolerant() bool {
+	return e.malformedPolicy == TolerantMalformed
+}
@@ -0,0 +1,361 @@
+package unmarshal
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"unsafe"
+
+	jsonunmarshal "github.com/viant/structology/encoding/json/unmarshal"
+	"github.com/viant/structology/encoding/jsontab/internal/plan"
+	"github.com/viant/xunsafe"
+)
+
+// rowSlice grows a slice of rows in place, handing out storage for one row at a time.
+type rowSlice struct {
+	value     reflect.Value
+	len       int
+	elemType  reflect.Type
+	elemIsPtr bool
+}
+
+func newRowSlice(sliceType reflect.Type, capacity int) *rowSlice {
+	return &rowSlice{
+		value:     reflect.MakeSlice(sliceType, capacity, capacity),
+		elemType:  sliceType.Elem(),
+		elemIsPtr: sliceType.Elem().Kind() == reflect.Ptr,
+	}
+}
+
+func (r *rowSlice) next() unsafe.Pointer {
+	if r.len == r.value.Len() {
+		capacity := 2 * r.len
+		if capacity < 4 {
+			capacity = 4
+		}
+		grown := reflect.MakeSlice(r.value.Type(), capacity, capacity)
+		reflect.Copy(grown, r.value)
+		r.value = grown
+	}
+	slot := r.value.Index(r.len)
+	r.len++
+	if r.elemIsPtr {
+		item := reflect.New(r.elemType.Elem())
+		slot.Set(item)
+		return item.UnsafePointer()
+	}
+	return slot.Addr().UnsafePointer()
+}
+
+func (r *rowSlice) slice() reflect.Value {
+	return r.value.Slice(0, r.len)
+}
+
+// decodeJSONTableInto decodes the `[[headers],[row]...]` form in a single scan, writing cells straight into fields.
+func (e *Engine) decodeJSONTableInto(data []byte, p *plan.Type, rootVal reflect.Value, rootIsSlice bool) error {
+	s := jsonunmarshal.NewScanner(data, nil)
+	var next func() unsafe.Pointer
+	var rows *rowSlice
+	if rootIsSlice {
+		rows = newRowSlice(rootVal.Type(), 0)
+		next = rows.next
+	} else {
+		next = firstRowTarget(rootVal, p.Type)
+	}
+	if _, err := e.scanTable(s, p, "", 0, next); err != nil {
+		return err
+	}
+	if !s.EOF() {
+		return fmt.Errorf("unexpected trailing data at %d", s.Pos())
+	}
+	if rootIsSlice {
+		rootVal.Set(rows.slice())
+	}
+	return nil
+}
+
+// firstRowTarget decodes the first row into target (a struct or struct pointer) and discards the rest.
+func firstRowTarget(target reflect.Value, rowType reflect.Type) func() unsafe.Pointer {
+	assigned := false
+	return func() unsafe.Pointer {
+		if assigned {
+			return reflect.New(rowType).UnsafePointer()
+		}
+		assigned = true
+		if target.Kind() == reflect.Ptr {
+			if target.IsNil() {
+				target.Set(reflect.New(rowType))
+			}
+			target = target.Elem()
+		}
+		target.SetZero()
+		return target.Addr().UnsafePointer()
+	}
+}
+
+// scanTable reads one table; next supplies zeroed storage for each record.
+func (e *Engine) scanTable(s *jsonunmarshal.Scanner, p *plan.Type, path string, baseRow int, next func() unsafe.Pointer) (int, error) {
+	if !s.Consume('[') {
+		return 0, e.derr(path, baseRow, -1, fmt.Errorf("expected table array"))
+	}
+	if s.Consume(']') {
+		return 0, nil
+	}
+	headers, err := e.scanHeaders(s)
+	if err != nil {
+		if e.malformedPolicy == TolerantMalformed {
+			return 0, skipTableRest(s)
+		}
+		return 0, e.derr(path, baseRow, 0, fmt.Errorf("invalid header row: %w", err))
+	}
+	bound, err := e.boundForHeaders(p, headers, path)
+	if err != nil {
+		return 0, err
+	}
+	rows := 0
+	for i := 1; ; i++ {
+		if s.Consume(']') {
+			return rows, nil
+		}
+		if !s.Consume(',') {
+			return rows, e.derr(path, baseRow+i, -1, fmt.Errorf("expected ',' at %d", s.Pos()))
+		}
+		if s.Peek() != '[' {
+			if e.malformedPolicy == TolerantMalformed {
+				if err = s.Skip(); err != nil {
+					return rows, err
+				}
+				continue
+			}
+			return rows, e.derr(path, baseRow+i, -1, fmt.Errorf("record is not an array"))
+		}
+		rowPtr := next()
+		if p.Presence != nil {
+			_ = plan.EnsurePresenceHolder(rowPtr, p.Presence)
+		}
+		if err = e.scanRecord(s, p, rowPtr, bound, len(headers), path, baseRow+i); err != nil {
+			return rows, err
+		}
+		rows++
+	}
+}
+
+func (e *Engine) scanHeaders(s *jsonunmarshal.Scanner) ([]string, error) {
+	if s.Peek() != '[' {
+		if err := s.Skip(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("expected []interface{}")
+	}
+	s.Consume('[')
+	var headers []string
+	if s.Consume(']') {
+		return headers, nil
+	}
+	var invalid error
+	for {
+		if s.Peek() == '"' {
+			header, err := s.String()
+			if err != nil {
+				return nil, err
+			}
+			headers = append(headers, header)
+		} else {
+			if err := s.Skip(); err != nil {
+				return nil, err
+			}
+			invalid = fmt.Errorf("expected string header")
+		}
+		if s.Consume(']') {
+			return headers, invalid
+		}
+		if !s.Consume(',') {
+			return nil, fmt.Errorf("expected ',' at %d", s.Pos())
+		}
+	}
+}
+
+func skipTableRest(s *jsonunmarshal.Scanner) error {
+	for {
+		if s.Consume(']') {
+			return nil
+		}
+		if !s.Consume(',') {
+			return fmt.Errorf("expected ',' at %d", s.Pos())
+		}
+		if err := s.Skip(); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *Engine) scanRecord(s *jsonunmarshal.Scanner, p *plan.Type, rowPtr unsafe.Pointer, bound []boundColumn, headers int, path string, rowNum int) error {
+	s.Consume('[')
+	col := 0
+	if !s.Consume(']') {
+		bi := 0
+		for {
+			if bi < len(bound) && bound[bi].col == col {
+				if err := e.scanBoundCell(s, p, rowPtr, &bound[bi], path, rowNum); err != nil {
+					return err
+				}
+				bi++
+			} else if err := s.Skip(); err != nil {
+				return err
+			}
+			col++
+			if s.Consume(']') {
+				break
+			}
+			if !s.Consume(',') {
+				return e.derr(path, rowNum, col, fmt.Errorf("expected ',' at %d", s.Pos()))
+			}
+		}
+	}
+	if e.arityPolicy == ErrorOnArityMismatch && col != headers {
+		return e.derr(path, rowNum, -1, fmt.Errorf("arity mismatch: got %d want %d", col, headers))
+	}
+	return nil
+}
+
+// scanBoundCell decodes a cell of a bound column, resolving flattened parents when the cell is not null.
+func (e *Engine) scanBoundCell(s *jsonunmarshal.Scanner, p *plan.Type, rowPtr unsafe.Pointer, b *boundColumn, path string, rowNum int) error {
+	if len(b.parents) == 0 {
+		return e.scanCell(s, p, rowPtr, b.field, path, rowNum, b.col)
+	}
+	if s.Null() {
+		return nil
+	}
+	owner, ownerPtr := e.flatTarget(p, rowPtr, b)
+	return e.scanCell(s, owner, ownerPtr, b.field, joinPath(path, b.prefix), rowNum, b.col)
+}
+
+func (e *Engine) scanCell(s *jsonunmarshal.Scanner, p *plan.Type, rowPtr unsafe.Pointer, field *plan.Field, path string, rowNum, col int) error {
+	fieldPtr := field.XField.Pointer(rowPtr)
+	if field.Kind == plan.FieldScalar {
+		if s.Null() {
+			if !e.NullUnset {
+				e.markPresence(p, rowPtr, field.StructName)
+			}
+			return nil
+		}
+		var err error
+		if field.Cell == plan.CellValue {
+			err = e.scanScalar(s, fieldPtr, field.Type)
+		} else {
+			err = scanEncoded(s, fieldPtr, field.Type)
+		}
+		if err != nil {
+			return e.derr(joinPath(path, field.StructName), rowNum, col, err)
+		}
+		e.markPresence(p, rowPtr, field.StructName)
+		return nil
+	}
+	if s.Null() {
+		return nil
+	}
+	if s.Peek() != '[' {
+		if e.malformedPolicy == TolerantMalformed {
+			return s.Skip()
+		}
+		return e.derr(joinPath(path, field.StructName), rowNum, col, fmt.Errorf("expects nested table"))
+	}
+	decoded, err := e.scanChildTable(s, field, fieldPtr, joinPath(path, field.StructName), rowNum)
+	if err != nil {
+		return err
+	}
+	if decoded {
+		e.markPresence(p, rowPtr, field.StructName)
+	}
+	return nil
+}
+
+// scanChildTable decodes a nested table into a struct (first row) or struct slice field.
+func (e *Engine) scanChildTable(s *jsonunmarshal.Scanner, field *plan.Field, fieldPtr unsafe.Pointer, path string, rowNum int) (bool, error) {
+	if field.Kind == plan.FieldStruct {
+		target := reflect.NewAt(field.Type, fieldPtr).Elem()
+		n, err := e.scanTable(s, field.Child, path, rowNum, firstRowTarget(target, field.Child.Type))
+		return n > 0, err
+	}
+	rows := newRowSlice(field.Type, 0)
+	if _, err := e.scanTable(s, field.Child, path, rowNum, rows.next); err != nil {
+		return false, err
+	}
+	reflect.NewAt(field.Type, fieldPtr).Elem().Set(rows.slice())
+	return true, nil
+}
+
+func (e *Engine) scanScalar(s *jsonunmarshal.Scanner, ptr unsafe.Pointer, rType reflect.Type) error {
+	if s.Null() {
+		return nil
+	}
+	if rType.Kind() == reflect.Ptr {
+		target := xunsafe.SafeDerefPointer(ptr, rType)
+		return e.scanScalar(s, target, rType.Elem())
+	}
+	switch s.Peek() {
+	case '"':
+		value, err := s.String()
+		if err != nil {
+			return err
+		}
+		if rType.Kind() == reflect.String {
+			*xunsafe.AsStringPtr(ptr) = value
+			return nil
+		}
+		return e.assignScalar(ptr, rType, value)
+	case '{', '[', 't', 'f':
+		value, err := s.Value()
+		if err != nil {
+			return err
+		}
+		return e.assignScalar(ptr, rType, value)
+	}
+	raw, err := s.RawValue()
+	if err != nil {
+		return err
+	}
+	switch rType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, ok := parseInt64Bytes(raw); ok {
+			return setIntKind(ptr, rType.Kind(), i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if u, ok := parseUint64Bytes(raw); ok {
+			return setUintKind(ptr, rType.Kind(), u)
+		}
+	}
+	f, err := strconv.ParseFloat(string(raw), 64)
+	if err != nil {
+		return fmt.Errorf("expected number")
+	}
+	return e.assignScalar(ptr, rType, f)
+}
+
+// scanEncoded decodes a JSON or text-marshaler cell with encoding/json, keeping the raw value intact.
+func scanEncoded(s *jsonunmarshal.Scanner, ptr unsafe.Pointer, rType reflect.Type) error {
+	raw, err := s.RawValue()
+	if err != nil {
+		return err
+	}
+	return stdjson.Unmarshal(raw, reflect.NewAt(rType, ptr).Interface())
+}
+
+// decodeNestedCell decodes a nested table held in a CSV cell.
+func (e *Engine) decodeNestedCell(raw []byte, p *plan.Type, rowPtr unsafe.Pointer, field *plan.Field, path string, rowNum, col int) error {
+	if e.malformedPolicy == TolerantMalformed {
+		probe := jsonunmarshal.NewScanner(raw, nil)
+		if probe.Skip() != nil || !probe.EOF() {
+			return nil
+		}
+	}
+	s := jsonunmarshal.NewScanner(raw, nil)
+	if err := e.scanCell(s, p, rowPtr, field, path, rowNum, col); err != nil {
+		return err
+	}
+	if !s.EOF() {
+		return e.derr(joinPath(path, field.StructName), rowNum, col, fmt.Errorf("unexpected trailing data at %d", s.Pos()))
+	}
+	return nil
+}
@@ -61,12 +61,20 @@ func (p *Marker) SetAll(ptr unsafe.Pointer, flag bool) error {
 }
 
 func (p *Marker) CanUseHolder(ptr unsafe.Pointer) bool {
-	if p.holder == nil || p.holder.IsNil(ptr) {
+	if p.holder == nil || p.holderIsNil(ptr) {
 		return false
 	}
 	return true
 }
 
+// holderIsNil reports whether a pointer holder is unallocated; value holders are never nil
+func (p *Marker) holderIsNil(ptr unsafe.Pointer) bool {
+	if p.holder.Kind() != reflect.Ptr {
+		return ptr == nil
+	}
+	return p.holder.IsNil(ptr)
+}
+
 // Set sets a field marker
 func (p *Marker) Set(ptr unsafe.Pointer, index int, flag bool) error {
 	if !p.CanUseHolder(ptr) {
@@ -82,17 +90,14 @@ func (p *Marker) Set(ptr unsafe.Pointer, index int, flag bool) error {
 
 // IsSet returns true if field has been set
 func (p *Marker) IsSet(ptr unsafe.Pointer, index int) bool {
-	if p == nil || p.holder == nil || p.holder.IsNil(ptr) {
+	if p == nil || p.holder == nil || p.holderIsNil(ptr) {
 		return true //we do not have field presence provider so we assume all fields are set
 	}
-	if p.holder.IsNil(ptr) {
-		return true //holder is nil
-	}
 	return p.has(ptr, index)
 }
 
 func (p *Marker) EnsureHolder(ptr unsafe.Pointer) {
-	if !p.holder.IsNil(ptr) {
+	if !p.holderIsNil(ptr) {
 		return
 	}
 	isPtr := p.holder.Type.Kind() == reflect.Ptr
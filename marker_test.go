@@ -112,3 +112,59 @@ func TestMarker_IsSet(t *testing.T) {
 	}
 
 }
+
+func TestMarker_Holders(t *testing.T) {
+	type EntityHas struct {
+		Id   bool
+		Name bool
+	}
+	type ValueEntity struct {
+		Id   int
+		Name string
+		Has  EntityHas `setMarker:"true"`
+	}
+	type PtrEntity struct {
+		Id   int
+		Name string
+		Has  *EntityHas `setMarker:"true"`
+	}
+
+	t.Run("value holder", func(t *testing.T) {
+		value := &ValueEntity{}
+		marker, err := NewMarker(reflect.TypeOf(value))
+		if !assert.Nil(t, err) {
+			return
+		}
+		ptr := xunsafe.AsPointer(value)
+		assert.True(t, marker.CanUseHolder(ptr))
+		assert.False(t, marker.IsSet(ptr, marker.Index("Name")))
+		marker.EnsureHolder(ptr)
+		assert.Nil(t, marker.Set(ptr, marker.Index("Name"), true))
+		assert.True(t, marker.IsSet(ptr, marker.Index("Name")))
+		assert.False(t, marker.IsSet(ptr, marker.Index("Id")))
+		assert.True(t, value.Has.Name)
+	})
+
+	t.Run("pointer holder", func(t *testing.T) {
+		value := &PtrEntity{}
+		marker, err := NewMarker(reflect.TypeOf(value))
+		if !assert.Nil(t, err) {
+			return
+		}
+		ptr := xunsafe.AsPointer(value)
+		assert.False(t, marker.CanUseHolder(ptr))
+		assert.True(t, marker.IsSet(ptr, marker.Index("Name")), "nil holder assumes all fields are set")
+		assert.NotNil(t, marker.Set(ptr, marker.Index("Name"), true))
+		marker.EnsureHolder(ptr)
+		if !assert.NotNil(t, value.Has) {
+			return
+		}
+		assert.False(t, marker.IsSet(ptr, marker.Index("Name")))
+		assert.Nil(t, marker.Set(ptr, marker.Index("Name"), true))
+		assert.True(t, marker.IsSet(ptr, marker.Index("Name")))
+		assert.False(t, marker.IsSet(ptr, marker.Index("Id")))
+		holder := value.Has
+		marker.EnsureHolder(ptr)
+		assert.Same(t, holder, value.Has, "allocated holder is kept")
+	})
+}